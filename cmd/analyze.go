@@ -4,19 +4,22 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/spf13/cobra"
+	"github.com/agnivo988/Repo-lyzer/internal/analysis"
 	"github.com/agnivo988/Repo-lyzer/internal/analyzer"
-	"github.com/agnivo988/Repo-lyzer/internal/github"
 	"github.com/agnivo988/Repo-lyzer/internal/output"
+	"github.com/spf13/cobra"
 )
 
+// allowedLicenses is the --allowed-licenses policy: SPDX IDs and license
+// categories the analyzed repository's license must be among.
+var allowedLicenses []string
+
 func RunAnalyze(owner, repo string) error {
 	args := []string{owner + "/" + repo}
 	analyzeCmd.SetArgs(args)
 	return analyzeCmd.Execute()
 }
 
-
 var analyzeCmd = &cobra.Command{
 	Use:   "analyze owner/repo",
 	Short: "Analyze a GitHub repository",
@@ -27,56 +30,100 @@ var analyzeCmd = &cobra.Command{
 			return fmt.Errorf("repository must be in owner/repo format")
 		}
 
-		client := github.NewClient()
-		repo, err := client.GetRepo(parts[0], parts[1])
+		ctx, cancel := commandContext(cmd)
+		defer cancel()
+
+		client, err := newClient()
+		if err != nil {
+			return err
+		}
+		source, err := newSource(client)
 		if err != nil {
 			return err
 		}
 
-		langs, _ := client.GetLanguages(parts[0], parts[1])
-		commits, _ := client.GetCommits(parts[0], parts[1], 365)
-         
-		
-		score := analyzer.CalculateHealth(repo, commits)
-		activity := analyzer.CommitsPerDay(commits)
-		contributors, err := client.GetContributors(parts[0], parts[1])
-            if err != nil {
-	              return err
-                     }
-
-					 busFactor, busRisk := analyzer.BusFactor(contributors)
-
-		maturityScore, maturityLevel :=
-			analyzer.RepoMaturityScore(
-				repo,
-				len(commits),
-				len(contributors),
-				false,
-			)
-
-		summary := analyzer.BuildRecruiterSummary(
-			repo.FullName,
-			repo.Forks,
-		    repo.Stars,
-			len(commits),
-			len(contributors),
-			maturityScore,
-			maturityLevel,
-			busFactor,
-			busRisk,
-		)
+		opts, err := analysisOptions(client)
+		if err != nil {
+			return err
+		}
+		result, err := analysis.Run(ctx, source, parts[0], parts[1], opts)
+		if err != nil {
+			return explainError(err, args[0])
+		}
 
-		output.PrintRepo(repo)
-		output.PrintLanguages(langs)
-		output.PrintCommitActivity(activity,14)
-		output.PrintHealth(score)
-		output.PrintGitHubAPIStatus(client)
-		output.PrintRecruiterSummary(summary)
+		output.PrintRepo(result.Repo)
+		output.PrintLanguages(result.Languages)
+		output.PrintCommitActivity(analyzer.CommitsPerDay(result.Commits), 14)
+		if result.Available(analysis.SectionHealth) {
+			output.PrintHealth(result.HealthScore)
+			output.PrintHealthBreakdown(result.Health)
+		} else {
+			output.PrintUnavailable("🏆 Repo Health Score", result.Status(analysis.SectionHealth))
+		}
+		if result.Available(analysis.SectionCommunityStandards) {
+			output.PrintCommunityStandards(result.CommunityStandards)
+		} else {
+			output.PrintUnavailable("📋 Community Standards", result.Status(analysis.SectionCommunityStandards))
+		}
+		if result.Available(analysis.SectionLicensing) {
+			output.PrintLicense(result.License)
+		} else {
+			output.PrintUnavailable("⚖️ License", result.Status(analysis.SectionLicensing))
+		}
+		if len(allowedLicenses) > 0 {
+			output.PrintLicensePolicy(result.License, allowedLicenses)
+		}
+		if result.Available(analysis.SectionSecurity) {
+			output.PrintSecurity(result.Security)
+		} else {
+			output.PrintUnavailable("🔒 Security", result.Status(analysis.SectionSecurity))
+		}
+		if result.Available(analysis.SectionDependencies) {
+			output.PrintDependencies(result.Dependencies)
+		} else {
+			output.PrintUnavailable("🧩 Dependencies", result.Status(analysis.SectionDependencies))
+		}
+		if result.Vulnerabilities != nil {
+			output.PrintVulnerabilities(*result.Vulnerabilities)
+		}
+		if result.Available(analysis.SectionIssueHealth) {
+			output.PrintIssueHealth(result.IssueHealth)
+		} else {
+			output.PrintUnavailable("🐛 Issue Health", result.Status(analysis.SectionIssueHealth))
+		}
+		if result.Available(analysis.SectionReleaseHealth) {
+			output.PrintReleaseHealth(result.ReleaseHealth)
+		} else {
+			output.PrintUnavailable("🏷️ Release Health", result.Status(analysis.SectionReleaseHealth))
+		}
+		if result.Available(analysis.SectionPRHealth) {
+			output.PrintPRHealth(result.PRHealth)
+		} else {
+			output.PrintUnavailable("🔀 Pull Request Health", result.Status(analysis.SectionPRHealth))
+		}
+		if result.Available(analysis.SectionTruckFactor) {
+			output.PrintTruckFactor(result.TruckFactor)
+		} else if opts.CommitStats > 0 {
+			output.PrintUnavailable("🚚 Truck Factor", result.Status(analysis.SectionTruckFactor))
+		}
+		if result.Available(analysis.SectionRetention) {
+			output.PrintRetention(result.Retention)
+		} else if history {
+			output.PrintUnavailable("🌱 Contributor Retention", result.Status(analysis.SectionRetention))
+		}
+		output.PrintGitHubAPIStatus(ctx, client)
+		output.PrintRecruiterSummary(result.Summary)
+		output.PrintDataQuality(result)
 
+		if len(allowedLicenses) > 0 && !result.License.Allowed(allowedLicenses) {
+			return fmt.Errorf("license %s is not allowed by --allowed-licenses", output.LicenseText(result.License))
+		}
 		return nil
 	},
 }
 
 func init() {
+	analyzeCmd.Flags().StringSliceVar(&allowedLicenses, "allowed-licenses", nil,
+		"fail unless the license is one of these SPDX IDs or categories (permissive, weak-copyleft, strong-copyleft)")
 	rootCmd.AddCommand(analyzeCmd)
 }
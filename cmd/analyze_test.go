@@ -0,0 +1,411 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/agnivo988/Repo-lyzer/internal/analysis"
+)
+
+func TestAnalyzeCommand(t *testing.T) {
+	tests := []struct {
+		repo string
+		want []string
+	}{
+		{"octo-org/busy", []string{
+			"octo-org/busy │ 1280  │ 214",
+			"⭐ Stars: 1280",
+			"🍴 Forks: 214",
+			"Repo Health Score : 98/100",
+			"Profile default: base 50",
+			"7 of 9 community files",
+			"150 recent commits (more than 10)",
+			"issue handling Healthy, 12 open",
+			"📦 Commits (1y): 150",
+			"👥 Contributors: 5",
+			"🏗️ Maturity: Production-Ready ( 100 )",
+			"⚠️ Bus Factor: 2 - Medium Risk",
+			"🚚 Truck Factor : 2 (Medium Risk)",
+			"Based on       : 10 files changed by 100 commits",
+			"👤 alice          3 files: README.md, cmd/main.go, internal/server/server.go",
+			"📋 Community Standards : 77% (7 of 9)",
+			"✅ CODE_OF_CONDUCT  docs/CODE_OF_CONDUCT.md",
+			"✅ Issue templates  .github/ISSUE_TEMPLATE/bug_report.md",
+			"❌ SECURITY         missing",
+			"⚖️ License : MIT (permissive)",
+			"GitHub         : MIT",
+			"LICENSE        : MIT (100% match)",
+			"Headers        : 2 checked, 1 disagree",
+			"⚠️ internal/server/server.go says GPL-3.0-only",
+			"🔒 Security : 50/100 (Moderate)",
+			"✅ Dependency updates Dependabot",
+			"✅ Code scanning      CodeQL",
+			"❌ Pinned actions     1 of 5 not pinned",
+			"❌ No secrets         1 found in 6 scanned files",
+			"✅ Branch protection  main is protected: 1 review, 2 status checks",
+			"config/deploy.yml: AWS access key ID committed",
+			".github/workflows/ci.yml: actions/checkout@v4 is not pinned to a commit SHA",
+			"🧩 Dependencies : 3 direct, 1 transitive",
+			"Manifests      : go.mod (Go)",
+			"Pinned         : 3 of 3 direct (100%)",
+			"🔁 go.mod: replace golang.org/x/sync => golang.org/x/sync v0.6.0",
+			"⚠️ Go github.com/go-chi/chi required at majors 1, 5",
+			"📋 Community Standards: 77%",
+			"🐛 Issue Health : Healthy",
+			"Open / Closed  : 12 / 12 (12 stale)",
+			"First Response : 8h (median of 12)",
+			"Time to Close  : 7.5d (median of 12)",
+			"Backlog Age    : >1y 12",
+			"Labels         : bug 10 · enhancement 4 · question 4",
+			"🐛 Issue Health: Healthy",
+			"Releases       : 6 (1 pre, 17%)",
+			"Tags           : 7",
+			"Cadence        : every 90.0d (median)",
+			"Semver         : 83%",
+			"Changelog      : release notes on 5 of 6",
+			"🔀 Pull Request Health : Healthy",
+			"Merge Rate     : 78% (7 merged, 1 open)",
+			"Time to Review : 5h (median of 9)",
+			"Time to Merge  : 2.0d (median of 7)",
+			"Sizes          : XS 2 · S 4 · M 2 · L 1 · XL 1",
+			"🔀 PR Health: Healthy",
+			"Requests    : 4990 / 5000",
+		}},
+		{"octo-org/solo", []string{
+			"Repo Health Score : 58/100",
+			"📦 Commits (1y): 3",
+			"🏗️ Maturity: Prototype ( 35 )",
+			"⚠️ Bus Factor: 1 - High Risk",
+			"📋 Community Standards : 0% (0 of 9)",
+			"⚖️ License : None found",
+			"Headers        : 1 checked, 0 disagree",
+			"🔒 Security : 35/100 (Weak)",
+			"main can be pushed to and force-pushed by anyone with write access",
+			"🧩 Dependencies : 0 direct, 0 transitive",
+			"No dependency manifests found",
+			"🐛 Issue Health : No issues",
+			"🏷️ Release Health : No releases",
+			"🔀 PR Health: No PRs",
+		}},
+		// No commits fixture: the section fails and dependent scores are
+		// reported as unavailable instead of as a dead repository.
+		{"octo-org/locked", []string{
+			"Repo Health Score : unavailable (not computed: commits unavailable)",
+			"🏗️ Maturity: Unknown ( 0 )",
+			"🚚 Truck Factor : unavailable (not computed: commits unavailable)",
+			"⚠️ Bus Factor: 1 - High Risk (estimated from commit shares)",
+			"⚠️ Data Quality",
+			"❌ commits       failed",
+			"🐛 Issue Health: Unknown",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.repo, func(t *testing.T) {
+			out, err := runCommand(t, "analyze", tt.repo)
+			if err != nil {
+				t.Fatalf("analyze %s: %v", tt.repo, err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(out, want) {
+					t.Errorf("output does not contain %q:\n%s", want, out)
+				}
+			}
+		})
+	}
+}
+
+func TestAnalyzeCommandErrors(t *testing.T) {
+	tests := []struct {
+		repo string
+		want string
+	}{
+		{"octo-org/missing", "repository octo-org/missing not found"},
+		{"not-a-repo", "owner/repo format"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.repo, func(t *testing.T) {
+			_, err := runCommand(t, "analyze", tt.repo)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("analyze %s error = %v, want %q", tt.repo, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyzeCommandReportsAuthSource(t *testing.T) {
+	t.Cleanup(func() { tokens = nil })
+
+	out, err := runCommand(t, "analyze", "octo-org/solo", "--token", "flag-token")
+	if err != nil {
+		t.Fatalf("analyze: %v", err)
+	}
+	if !strings.Contains(out, "Mode        : Authenticated via --token") {
+		t.Errorf("output does not name the token source:\n%s", out)
+	}
+}
+
+func TestAnalyzeCommandHealthProfile(t *testing.T) {
+	t.Cleanup(func() { healthProfile = "" })
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	tests := []struct {
+		name    string
+		profile string
+		want    string
+		wantErr string
+	}{
+		{"yaml", write("strict.yaml", "base: 0\nweights:\n  commits: 60\n  issues: 40\n"), "Repo Health Score : 40/100", ""},
+		{"json", write("team.json", `{"name": "team", "base": 20, "weights": {"issues": 15}}`), "Profile team: base 20", ""},
+		{"unknown signal", write("typo.yaml", "weights:\n  comits: 20\n"), "", "unknown signals comits"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := runCommand(t, "analyze", "octo-org/solo", "--health-profile", tt.profile)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("analyze: %v", err)
+			}
+			if !strings.Contains(out, tt.want) {
+				t.Errorf("output does not contain %q:\n%s", tt.want, out)
+			}
+		})
+	}
+}
+
+func TestAnalyzeCommandOSVDatabase(t *testing.T) {
+	t.Cleanup(func() { osvDB = "" })
+	dir := t.TempDir()
+	advisory := `{
+		"id": "EX-2024-0001",
+		"summary": "Path traversal in router",
+		"affected": [{
+			"package": {"ecosystem": "Go", "name": "github.com/go-chi/chi"},
+			"ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}, {"fixed": "1.5.6"}]}]
+		}],
+		"database_specific": {"severity": "HIGH"}
+	}`
+	if err := os.WriteFile(filepath.Join(dir, "EX-2024-0001.json"), []byte(advisory), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		repo    string
+		db      string
+		want    []string
+		wantErr string
+	}{
+		{"vulnerable", "octo-org/busy", dir, []string{
+			"Repo Health Score : 88/100",
+			"1 known vulnerabilities in 4 checked dependencies",
+			"🔒 Security : 41/100 (Weak)",
+			"❌ Vulnerabilities    1 found in 1 of 4 checked dependencies",
+			"🚨 Vulnerabilities : 1 in 1 packages",
+			"Checked        : 4 dependencies with exact versions",
+			"Go github.com/go-chi/chi@1.5.5 EX-2024-0001 (fixed in 1.5.6): Path traversal in router",
+		}, ""},
+		{"nothing to check", "octo-org/solo", dir, []string{
+			"Repo Health Score : 58/100",
+			"0 known vulnerabilities in 0 checked dependencies",
+			"✅ Vulnerabilities    none in 0 checked dependencies",
+		}, ""},
+		{"missing", "octo-org/solo", filepath.Join(dir, "nope"), nil, "reading OSV database"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := runCommand(t, "analyze", tt.repo, "--osv-db", tt.db)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("analyze: %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(out, want) {
+					t.Errorf("output does not contain %q:\n%s", want, out)
+				}
+			}
+		})
+	}
+}
+
+func TestAnalyzeCommandAllowedLicenses(t *testing.T) {
+	t.Cleanup(func() { allowedLicenses = nil })
+
+	tests := []struct {
+		name    string
+		repo    string
+		policy  string
+		want    string
+		wantErr string
+	}{
+		{"by category", "octo-org/busy", "permissive", "✅ License Policy : MIT (permissive) is allowed (permissive)", ""},
+		{"not listed", "octo-org/busy", "Apache-2.0,strong-copyleft", "❌ License Policy : MIT (permissive) is not allowed", "license MIT (permissive) is not allowed"},
+		{"no license", "octo-org/solo", "MIT", "❌ License Policy : None found is not allowed (MIT)", "license None found is not allowed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allowedLicenses = nil
+			out, err := runCommand(t, "analyze", tt.repo, "--allowed-licenses", tt.policy)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("analyze: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("error = %v, want %q", err, tt.wantErr)
+			}
+			if !strings.Contains(out, tt.want) {
+				t.Errorf("output does not contain %q:\n%s", tt.want, out)
+			}
+		})
+	}
+}
+
+func TestAnalyzeCommandCommitStats(t *testing.T) {
+	t.Cleanup(func() { commitStats = analysis.DefaultCommitStats })
+
+	out, err := runCommand(t, "analyze", "octo-org/solo", "--commit-stats", "2")
+	if err != nil {
+		t.Fatalf("analyze: %v", err)
+	}
+	for _, want := range []string{
+		"🚚 Truck Factor : 1 (High Risk)",
+		"Based on       : 1 files changed by 2 commits",
+		"👤 sam",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output does not contain %q:\n%s", want, out)
+		}
+	}
+
+	out, err = runCommand(t, "analyze", "octo-org/solo", "--commit-stats", "0")
+	if err != nil {
+		t.Fatalf("analyze: %v", err)
+	}
+	if strings.Contains(out, "Truck Factor") || !strings.Contains(out, "⚠️ Bus Factor: 1 - High Risk (estimated from commit shares)") {
+		t.Errorf("the bus factor is not estimated from commit shares without --commit-stats:\n%s", out)
+	}
+}
+
+func TestAnalyzeCommandWithoutToken(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("GH_CONFIG_DIR", t.TempDir())
+	t.Cleanup(func() { commitStats = analysis.DefaultCommitStats })
+
+	// The requests made per pull request, issue and commit are left out.
+	out, err := runAs(t, "", "analyze", "octo-org/busy")
+	if err != nil {
+		t.Fatalf("analyze: %v", err)
+	}
+	for _, want := range []string{
+		"Mode        : Unauthenticated",
+		"First Response : n/a",
+		"Time to Review : n/a",
+		"⚠️ Bus Factor: 3 - Low Risk (estimated from commit shares)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output does not contain %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "Truck Factor") {
+		t.Errorf("commit stats fetched without a token:\n%s", out)
+	}
+
+	// Unless they are asked for.
+	out, err = runAs(t, "", "analyze", "octo-org/busy", "--commit-stats", "30")
+	if err != nil {
+		t.Fatalf("analyze: %v", err)
+	}
+	if !strings.Contains(out, "🚚 Truck Factor : 2 (Medium Risk)") {
+		t.Errorf("--commit-stats ignored without a token:\n%s", out)
+	}
+}
+
+func TestAnalyzeCommandIdentities(t *testing.T) {
+	dir := t.TempDir()
+	mailmapFile := filepath.Join(dir, ".mailmap")
+	if err := os.WriteFile(mailmapFile, []byte("<alice@example.com> <bob@example.com>\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    string
+		wantErr string
+	}{
+		{"bots left out", nil, "👥 Contributors: 5 (1 bot left out)", ""},
+		{"bots included", []string{"--include-bots"}, "👥 Contributors: 6\n", ""},
+		{"more bots", []string{"--bot", "erin"}, "👥 Contributors: 4 (2 bots left out)", ""},
+		{"mailmap", []string{"--mailmap", mailmapFile}, "👥 Contributors: 4 (1 bot left out)", ""},
+		{"missing mailmap", []string{"--mailmap", filepath.Join(dir, "nope")}, "", "reading mailmap"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Cleanup(func() { bots, mailmap, includeBots = nil, "", false })
+			out, err := runCommand(t, append([]string{"analyze", "octo-org/busy"}, tt.args...)...)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("analyze: %v", err)
+			}
+			if !strings.Contains(out, tt.want) {
+				t.Errorf("output does not contain %q:\n%s", tt.want, out)
+			}
+		})
+	}
+}
+
+func TestAnalyzeCommandHistory(t *testing.T) {
+	t.Cleanup(func() { history = false })
+
+	out, err := runCommand(t, "analyze", "octo-org/busy", "--history")
+	if err != nil {
+		t.Fatalf("analyze: %v", err)
+	}
+	for _, want := range []string{
+		"🌱 Contributor Retention :",
+		"Contributors   : 5 with 150 commits since 2026-07-24",
+		"One-time       : 0 of 5 (0%)",
+		"Core team      : 3 people behind 80% of commits",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output does not contain %q:\n%s", want, out)
+		}
+	}
+
+	history = false
+	out, err = runCommand(t, "analyze", "octo-org/busy")
+	if err != nil {
+		t.Fatalf("analyze: %v", err)
+	}
+	if strings.Contains(out, "Contributor Retention") {
+		t.Errorf("retention printed without --history:\n%s", out)
+	}
+}
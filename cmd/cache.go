@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+	"github.com/spf13/cobra"
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the on-disk GitHub response cache",
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Delete all cached GitHub responses",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir := cacheDir
+		if dir == "" {
+			var err error
+			if dir, err = github.DefaultCacheDir(); err != nil {
+				return err
+			}
+		}
+
+		removed, err := github.ClearCache(dir)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("🧹 Removed %d cached responses from %s\n", removed, dir)
+		return nil
+	},
+}
+
+func init() {
+	cacheCmd.AddCommand(cacheClearCmd)
+	rootCmd.AddCommand(cacheCmd)
+}
@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCacheClear(t *testing.T) {
+	dir := t.TempDir()
+	t.Cleanup(func() { cacheDir = "" })
+	for _, name := range []string{"a.json", "b.json", "c.json.123.tmp", "notes.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("{}"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	out, err := runCommand(t, "cache", "clear", "--cache-dir", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Removed 2 cached responses from " + dir; !strings.Contains(out, want) {
+		t.Errorf("output = %q, want %q", out, want)
+	}
+	left, _ := filepath.Glob(filepath.Join(dir, "*"))
+	if len(left) != 1 || filepath.Base(left[0]) != "notes.txt" {
+		t.Errorf("left in the cache directory: %v", left)
+	}
+}
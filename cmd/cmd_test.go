@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github/githubtest"
+	"github.com/spf13/pflag"
+)
+
+// runCommand executes the root command with args against the fixture server,
+// authenticated with a test token, and returns what it printed to stdout.
+func runCommand(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	return runAs(t, "fixture-token", args...)
+}
+
+// runAs is runCommand with token in GITHUB_TOKEN; "" leaves the command
+// without one.
+func runAs(t *testing.T, token string, args ...string) (string, error) {
+	t.Helper()
+	t.Setenv("GITHUB_TOKEN", token)
+	t.Setenv("GH_TOKEN", "")
+
+	srv := githubtest.NewServer(t)
+	args = append(args, "--api-url", srv.URL, "--no-cache")
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	var out bytes.Buffer
+	done := make(chan struct{})
+	go func() {
+		io.Copy(&out, r)
+		close(done)
+	}()
+
+	// Flags keep their values between runs; the tests reset those they
+	// set, and whether they were set is reset here.
+	rootCmd.PersistentFlags().VisitAll(func(f *pflag.Flag) { f.Changed = false })
+	rootCmd.SetArgs(args)
+	rootCmd.SetOut(io.Discard)
+	rootCmd.SetErr(io.Discard)
+	runErr := rootCmd.Execute()
+
+	w.Close()
+	<-done
+	return out.String(), runErr
+}
@@ -9,8 +9,9 @@ import (
 
 	"os"
 
+	"github.com/agnivo988/Repo-lyzer/internal/analysis"
 	"github.com/agnivo988/Repo-lyzer/internal/analyzer"
-	"github.com/agnivo988/Repo-lyzer/internal/github"
+	"github.com/agnivo988/Repo-lyzer/internal/output"
 )
 
 func RunCompare(r1, r2 string) error {
@@ -18,7 +19,6 @@ func RunCompare(r1, r2 string) error {
 	return compareCmd.Execute()
 }
 
-
 var compareCmd = &cobra.Command{
 	Use:   "compare owner1/repo1 owner2/repo2",
 	Short: "Compare two GitHub repositories",
@@ -33,32 +33,33 @@ var compareCmd = &cobra.Command{
 			return fmt.Errorf("repositories must be in owner/repo format")
 		}
 
-		client := github.NewClient()
+		ctx, cancel := commandContext(cmd)
+		defer cancel()
 
-		repo1, err := client.GetRepo(r1[0], r1[1])
+		client, err := newClient()
+		if err != nil {
+			return err
+		}
+		source, err := newSource(client)
 		if err != nil {
 			return err
 		}
 
-		commits1, _ := client.GetCommits(r1[0], r1[1], 14)
-		contributors1, _ := client.GetContributors(r1[0], r1[1])
-		bus1, risk1 := analyzer.BusFactor(contributors1)
-
-		maturityScore1, maturityLevel1 :=
-			analyzer.RepoMaturityScore(repo1, len(commits1), len(contributors1), false)
-
-		// ---------- Fetch Repo 2 ----------
-		repo2, err := client.GetRepo(r2[0], r2[1])
+		opts, err := analysisOptions(client)
 		if err != nil {
 			return err
 		}
 
-		commits2, _ := client.GetCommits(r2[0], r2[1], 14)
-		contributors2, _ := client.GetContributors(r2[0], r2[1])
-		bus2, risk2 := analyzer.BusFactor(contributors2)
+		a, err := analysis.Run(ctx, source, r1[0], r1[1], opts)
+		if err != nil {
+			return explainError(err, args[0])
+		}
 
-		maturityScore2, maturityLevel2 :=
-			analyzer.RepoMaturityScore(repo2, len(commits2), len(contributors2), false)
+		b, err := analysis.Run(ctx, source, r2[0], r2[1], opts)
+		if err != nil {
+			return explainError(err, args[1])
+		}
+		repo1, repo2 := a.Repo, b.Repo
 
 		// ---------- Output Table ----------
 		fmt.Println("\n📊 Repository Comparison")
@@ -77,32 +78,82 @@ var compareCmd = &cobra.Command{
 		})
 
 		table.Append([]string{"📦 Commits (1y)",
-			fmt.Sprintf("%d", len(commits1)),
-			fmt.Sprintf("%d", len(commits2)),
+			cell(a, analysis.SectionCommits, fmt.Sprintf("%d", len(a.Commits))),
+			cell(b, analysis.SectionCommits, fmt.Sprintf("%d", len(b.Commits))),
 		})
 
 		table.Append([]string{"👥 Contributors",
-			fmt.Sprintf("%d", len(contributors1)),
-			fmt.Sprintf("%d", len(contributors2)),
+			cell(a, analysis.SectionContributors, fmt.Sprintf("%d", len(a.Contributors))),
+			cell(b, analysis.SectionContributors, fmt.Sprintf("%d", len(b.Contributors))),
 		})
 
 		table.Append([]string{"⚠️ Bus Factor",
-			fmt.Sprintf("%d (%s)", bus1, risk1),
-			fmt.Sprintf("%d (%s)", bus2, risk2),
+			cell(a, analysis.SectionBusFactor, busFactor(a)),
+			cell(b, analysis.SectionBusFactor, busFactor(b)),
 		})
 
 		table.Append([]string{"🏗️ Maturity",
-			fmt.Sprintf("%s (%d)", maturityLevel1, maturityScore1),
-			fmt.Sprintf("%s (%d)", maturityLevel2, maturityScore2),
+			cell(a, analysis.SectionMaturity, fmt.Sprintf("%s (%d)", a.MaturityLevel, a.MaturityScore)),
+			cell(b, analysis.SectionMaturity, fmt.Sprintf("%s (%d)", b.MaturityLevel, b.MaturityScore)),
+		})
+
+		table.Append([]string{"📋 Community Standards",
+			cell(a, analysis.SectionCommunityStandards, fmt.Sprintf("%d%%", a.CommunityStandards.Completeness)),
+			cell(b, analysis.SectionCommunityStandards, fmt.Sprintf("%d%%", b.CommunityStandards.Completeness)),
+		})
+
+		table.Append([]string{"⚖️ License",
+			cell(a, analysis.SectionLicensing, output.LicenseText(a.License)),
+			cell(b, analysis.SectionLicensing, output.LicenseText(b.License)),
+		})
+
+		table.Append([]string{"🔒 Security",
+			cell(a, analysis.SectionSecurity, fmt.Sprintf("%d (%s)", a.Security.Score, a.Security.Level)),
+			cell(b, analysis.SectionSecurity, fmt.Sprintf("%d (%s)", b.Security.Score, b.Security.Level)),
+		})
+
+		table.Append([]string{"🧩 Dependencies",
+			cell(a, analysis.SectionDependencies, output.DependenciesText(a.Dependencies)),
+			cell(b, analysis.SectionDependencies, output.DependenciesText(b.Dependencies)),
+		})
+
+		table.Append([]string{"🏷️ Releases",
+			cell(a, analysis.SectionReleaseHealth, releases(a)),
+			cell(b, analysis.SectionReleaseHealth, releases(b)),
+		})
+
+		table.Append([]string{"🐛 Issue Health",
+			cell(a, analysis.SectionIssueHealth, a.IssueHealth.Level),
+			cell(b, analysis.SectionIssueHealth, b.IssueHealth.Level),
+		})
+
+		table.Append([]string{"💬 Issue Response",
+			cell(a, analysis.SectionIssueHealth, issueResponse(a)),
+			cell(b, analysis.SectionIssueHealth, issueResponse(b)),
+		})
+
+		table.Append([]string{"🔀 PR Merge Rate",
+			cell(a, analysis.SectionPRHealth, prMergeRate(a)),
+			cell(b, analysis.SectionPRHealth, prMergeRate(b)),
+		})
+
+		table.Append([]string{"⏱️ PR Time to Merge",
+			cell(a, analysis.SectionPRHealth, prTimeToMerge(a)),
+			cell(b, analysis.SectionPRHealth, prTimeToMerge(b)),
 		})
 
 		table.Render()
+		if len(a.Degraded())+len(b.Degraded()) > 0 {
+			fmt.Println("*: based on incomplete data   n/a: not available")
+		}
 
 		// ---------- Verdict ----------
 		fmt.Println("\n Verdict")
-		if maturityScore1 > maturityScore2 {
+		if !a.Available(analysis.SectionMaturity) || !b.Available(analysis.SectionMaturity) {
+			fmt.Println("➡️ Not enough data to compare maturity.")
+		} else if a.MaturityScore > b.MaturityScore {
 			fmt.Printf("➡️ %s appears more mature and stable.\n", repo1.FullName)
-		} else if maturityScore2 > maturityScore1 {
+		} else if b.MaturityScore > a.MaturityScore {
 			fmt.Printf("➡️ %s appears more mature and stable.\n", repo2.FullName)
 		} else {
 			fmt.Println("➡️ Both repositories are similarly mature.")
@@ -112,7 +163,55 @@ var compareCmd = &cobra.Command{
 	},
 }
 
+// cell renders value for a table row backed by section: "n/a" when the
+// section is unavailable, flagged with "*" when it is incomplete.
+func cell(res *analysis.Result, section analysis.Section, value string) string {
+	switch status := res.Status(section); {
+	case !res.Available(section):
+		return "n/a"
+	case status.State != analysis.StateOK:
+		return value + " *"
+	}
+	return value
+}
+
+// busFactor formats the bus factor of res, marking one estimated from
+// commit shares rather than file ownership.
+func busFactor(res *analysis.Result) string {
+	if res.BusMethod == analyzer.BusFromCommits {
+		return fmt.Sprintf("%d (%s, by commits)", res.BusFactor, res.BusRisk)
+	}
+	return fmt.Sprintf("%d (%s)", res.BusFactor, res.BusRisk)
+}
+
+func prMergeRate(res *analysis.Result) string {
+	if res.PRHealth.Total == 0 {
+		return res.PRHealth.Level
+	}
+	return fmt.Sprintf("%.0f%% (%s)", res.PRHealth.MergeRate*100, res.PRHealth.Level)
+}
+
+func prTimeToMerge(res *analysis.Result) string {
+	if res.PRHealth.Merged == 0 {
+		return "-"
+	}
+	return analyzer.HumanDuration(res.PRHealth.MedianTimeToMerge)
+}
+
+func releases(res *analysis.Result) string {
+	if res.ReleaseHealth.Releases == 0 {
+		return res.ReleaseHealth.Level
+	}
+	return fmt.Sprintf("%d (%s)", res.ReleaseHealth.Releases, res.ReleaseHealth.Level)
+}
+
+func issueResponse(res *analysis.Result) string {
+	if res.IssueHealth.Responded == 0 {
+		return "-"
+	}
+	return analyzer.HumanDuration(res.IssueHealth.MedianTimeToFirstResponse)
+}
+
 func init() {
 	rootCmd.AddCommand(compareCmd)
 }
-
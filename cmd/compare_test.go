@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCompareCommand(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{"more mature first", []string{"octo-org/busy", "octo-org/solo"}, []string{
+			"Production-Ready (100)",
+			"Prototype (35)",
+			"No releases",
+			"MIT (permissive)",
+			"None found",
+			"3 direct, 1 transitive",
+			"octo-org/busy appears more mature and stable.",
+		}},
+		{"more mature second", []string{"octo-org/solo", "octo-org/busy"}, []string{
+			"octo-org/busy appears more mature and stable.",
+		}},
+		{"same repository", []string{"octo-org/solo", "octo-org/solo"}, []string{
+			"Both repositories are similarly mature.",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := runCommand(t, append([]string{"compare"}, tt.args...)...)
+			if err != nil {
+				t.Fatalf("compare: %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(out, want) {
+					t.Errorf("output does not contain %q:\n%s", want, out)
+				}
+			}
+		})
+	}
+}
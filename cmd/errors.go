@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+// explainError turns the typed errors of the GitHub client into messages
+// telling the user what went wrong with repo and what to do about it.
+func explainError(err error, repo string) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, github.ErrNotFound):
+		return fmt.Errorf("repository %s not found (private repositories need GITHUB_TOKEN)", repo)
+	case errors.Is(err, github.ErrUnauthorized):
+		return fmt.Errorf("GitHub rejected the credentials or none were found: check GITHUB_TOKEN or --token")
+	case errors.Is(err, github.ErrRateLimited):
+		if reset, ok := github.RateLimitReset(err); ok {
+			return fmt.Errorf("GitHub API rate limit exceeded until %s (tip: set GITHUB_TOKEN for a higher limit)",
+				reset.Format("15:04"))
+		}
+		return fmt.Errorf("GitHub API rate limit exceeded (tip: set GITHUB_TOKEN for a higher limit)")
+	case errors.Is(err, context.DeadlineExceeded):
+		return fmt.Errorf("analyzing %s timed out (raise --timeout or --request-timeout)", repo)
+	}
+	return err
+}
@@ -1,16 +1,30 @@
 package cmd
 
 import (
-	"fmt"
-	"os"
-
 	"github.com/agnivo988/Repo-lyzer/internal/ui"
+	"github.com/spf13/cobra"
 )
 
-func RunMenu() {
-	if err := ui.Run(); err != nil {
-		fmt.Println("Error running application:", err)
-		os.Exit(1)
+// runMenu starts the TUI, which analyzes repositories with the client,
+// backend and analysis options the persistent flags set, like the
+// commands do.
+func runMenu(cmd *cobra.Command, args []string) error {
+	client, err := newClient()
+	if err != nil {
+		return err
+	}
+	source, err := newSource(client)
+	if err != nil {
+		return err
 	}
+	opts, err := analysisOptions(client)
+	if err != nil {
+		return err
+	}
+	return ui.Run(source, opts)
 }
 
+func init() {
+	// Without a command, Repo-lyzer opens the TUI.
+	rootCmd.RunE = runMenu
+}
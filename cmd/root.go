@@ -1,21 +1,224 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"time"
 
+	"github.com/agnivo988/Repo-lyzer/internal/analysis"
+	"github.com/agnivo988/Repo-lyzer/internal/analyzer"
+	"github.com/agnivo988/Repo-lyzer/internal/github"
 	"github.com/spf13/cobra"
 )
 
+var (
+	overallTimeout time.Duration
+	requestTimeout time.Duration
+	noCache        bool
+	cacheDir       string
+	cacheTTL       time.Duration
+	apiURL         string
+	backend        string
+	concurrency    int
+	healthProfile  string
+	osvDB          string
+	commitStats    int
+	prDetails      int
+	issueDetails   int
+	history        bool
+	bots           []string
+	mailmap        string
+	includeBots    bool
+
+	tokens            []string
+	appID             int64
+	appInstallationID int64
+	appKeyPath        string
+)
+
 var rootCmd = &cobra.Command{
 	Use:   "Repo-lyzer",
 	Short: "Analyze GitHub repositories from the terminal",
 	Long:  "Repo-lyzer is a fast CLI tool written in Go to analyze GitHub repositories.",
 }
 
+func init() {
+	rootCmd.PersistentFlags().DurationVar(&overallTimeout, "timeout", 2*time.Minute,
+		"overall time limit for a command (0 disables it)")
+	rootCmd.PersistentFlags().DurationVar(&requestTimeout, "request-timeout", github.DefaultRequestTimeout,
+		"time limit for a single GitHub API request (0 disables it)")
+	rootCmd.PersistentFlags().StringVar(&apiURL, "api-url", "",
+		"GitHub REST API root, e.g. https://github.example.com/api/v3/ (default $GITHUB_API_URL or https://api.github.com/)")
+	rootCmd.PersistentFlags().StringVar(&backend, "backend", os.Getenv("REPOLYZER_BACKEND"),
+		"GitHub API to fetch data with: rest or graphql (graphql needs a token; default $REPOLYZER_BACKEND or rest)")
+	rootCmd.PersistentFlags().IntVar(&concurrency, "concurrency", analysis.DefaultConcurrency,
+		"maximum number of GitHub API requests in flight at once")
+	rootCmd.PersistentFlags().StringVar(&healthProfile, "health-profile", "",
+		"YAML or JSON file with the weights of the health score signals (default: the built-in profile)")
+	rootCmd.PersistentFlags().IntVar(&commitStats, "commit-stats", analysis.DefaultCommitStats,
+		"fetch the changed files of up to this many of the newest commits, one request each, to work out the bus factor from file ownership (0 skips them and estimates it from commit shares; without a token, 0 unless set)")
+	rootCmd.PersistentFlags().IntVar(&prDetails, "pr-details", analysis.DefaultPRDetails,
+		"fetch the size and reviews of up to this many of the newest pull requests, two requests each (without a token, 0 unless set)")
+	rootCmd.PersistentFlags().IntVar(&issueDetails, "issue-details", analysis.DefaultIssueDetails,
+		"fetch the comments of up to this many of the newest issues, one request each (without a token, 0 unless set)")
+	rootCmd.PersistentFlags().BoolVar(&history, "history", false,
+		"also fetch the whole commit history, up to 2000 commits, for contributor retention and the core team (past 2000, newcomers are counted from 90 days after the oldest commit fetched)")
+	rootCmd.PersistentFlags().StringArrayVar(&bots, "bot", nil,
+		"treat this login or git name as a bot, besides the known ones; a trailing * matches any rest (repeatable)")
+	rootCmd.PersistentFlags().BoolVar(&includeBots, "include-bots", false,
+		"count bots as contributors in the contributor metrics")
+	rootCmd.PersistentFlags().StringVar(&mailmap, "mailmap", "",
+		"git mailmap file mapping the emails people committed with to one identity each")
+	rootCmd.PersistentFlags().StringVar(&osvDB, "osv-db", os.Getenv("REPOLYZER_OSV_DB"),
+		"directory or zip of OSV advisories to check dependencies against, e.g. an all.zip from osv.dev (default $REPOLYZER_OSV_DB)")
+	rootCmd.PersistentFlags().StringArrayVar(&tokens, "token", nil,
+		"GitHub token; repeat to spread requests over a pool of tokens (default: GITHUB_TOKEN, GH_TOKEN, gh CLI login, ~/.netrc, then GITHUB_APP_* variables)")
+	rootCmd.PersistentFlags().Int64Var(&appID, "app-id", 0,
+		"authenticate as this GitHub App (needs --app-installation-id and --app-key)")
+	rootCmd.PersistentFlags().Int64Var(&appInstallationID, "app-installation-id", 0,
+		"GitHub App installation to request a token for")
+	rootCmd.PersistentFlags().StringVar(&appKeyPath, "app-key", "",
+		"path to the GitHub App's PEM private key")
+	rootCmd.PersistentFlags().BoolVar(&noCache, "no-cache", false,
+		"bypass the on-disk GitHub response cache")
+	rootCmd.PersistentFlags().DurationVar(&cacheTTL, "cache-ttl", github.DefaultCacheTTL,
+		"serve cached responses younger than this without revalidating")
+	rootCmd.PersistentFlags().StringVar(&cacheDir, "cache-dir", os.Getenv("REPOLYZER_CACHE_DIR"),
+		"directory of the GitHub response cache (default $REPOLYZER_CACHE_DIR or repo-lyzer/http in the user cache directory)")
+}
+
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Println(err)
 		os.Exit(1)
 	}
 }
+
+// commandContext derives the context a command runs under, applying the
+// --timeout flag.
+func commandContext(cmd *cobra.Command) (context.Context, context.CancelFunc) {
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if overallTimeout > 0 {
+		return context.WithTimeout(ctx, overallTimeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+// newClient builds a GitHub client configured from the persistent flags.
+func newClient() (*github.Client, error) {
+	creds, err := credentials()
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []github.Option{github.WithRequestTimeout(requestTimeout)}
+	if len(creds) > 1 {
+		opts = append(opts, github.WithTokenPool(creds...))
+	} else {
+		opts = append(opts, github.WithCredentials(append(creds, github.DefaultResolvers()...)...))
+	}
+	if apiURL != "" {
+		opts = append(opts, github.WithBaseURL(apiURL))
+	}
+	if !noCache {
+		opts = append(opts, github.WithCache(cacheDir, cacheTTL))
+	}
+	return github.NewClient(opts...), nil
+}
+
+// credentials returns the token sources given with --token and --app-*.
+// More than one make a token pool; otherwise the default chain follows them.
+func credentials() ([]github.Resolver, error) {
+	var resolvers []github.Resolver
+	for i, token := range tokens {
+		source := "--token"
+		if len(tokens) > 1 {
+			source = fmt.Sprintf("--token #%d", i+1)
+		}
+		resolvers = append(resolvers, github.FromToken(token, source))
+	}
+	if appID != 0 {
+		if appInstallationID == 0 || appKeyPath == "" {
+			return nil, fmt.Errorf("--app-id needs --app-installation-id and --app-key")
+		}
+		key, err := os.ReadFile(appKeyPath)
+		if err != nil {
+			return nil, fmt.Errorf("reading GitHub App key: %w", err)
+		}
+		resolvers = append(resolvers, github.FromApp(appID, appInstallationID, key))
+	}
+	return resolvers, nil
+}
+
+// Files read after the file tree, one request each, when the client has no
+// token.
+const (
+	unauthenticatedLicenseFiles    = 3
+	unauthenticatedSecurityFiles   = 5
+	unauthenticatedDependencyFiles = 5
+)
+
+// analysisOptions returns the analysis options set by the persistent flags.
+// Without a token GitHub allows 60 requests an hour, fewer than the default
+// per-item fetches take alone, so for an unauthenticated client those are
+// left out unless their flags are set, and fewer files are read.
+func analysisOptions(client *github.Client) (analysis.Options, error) {
+	opts := analysis.Options{
+		Concurrency:  concurrency,
+		FileTree:     true,
+		CommitStats:  commitStats,
+		PRDetails:    prDetails,
+		IssueDetails: issueDetails,
+		History:      history,
+	}
+	if !client.Authenticated() {
+		for flag, n := range map[string]*int{
+			"commit-stats":  &opts.CommitStats,
+			"pr-details":    &opts.PRDetails,
+			"issue-details": &opts.IssueDetails,
+		} {
+			if !rootCmd.PersistentFlags().Changed(flag) {
+				*n = 0
+			}
+		}
+		opts.LicenseFiles = unauthenticatedLicenseFiles
+		opts.SecurityFiles = unauthenticatedSecurityFiles
+		opts.DependencyFiles = unauthenticatedDependencyFiles
+	}
+	for _, n := range []*int{&opts.CommitStats, &opts.PRDetails, &opts.IssueDetails} {
+		if *n <= 0 {
+			*n = -1 // 0 would mean the default to Run
+		}
+	}
+	opts.Identities = analyzer.NewIdentities(bots...)
+	opts.Identities.IncludeBots = includeBots
+	if mailmap != "" {
+		if err := opts.Identities.LoadMailmap(mailmap); err != nil {
+			return opts, err
+		}
+	}
+	if healthProfile != "" {
+		profile, err := analyzer.LoadProfile(healthProfile)
+		if err != nil {
+			return opts, err
+		}
+		opts.HealthProfile = &profile
+	}
+	if osvDB != "" {
+		db, err := analyzer.LoadOSV(osvDB)
+		if err != nil {
+			return opts, err
+		}
+		opts.OSV = db
+	}
+	return opts, nil
+}
+
+// newSource wraps client in the backend selected with --backend.
+func newSource(client *github.Client) (github.RepoSource, error) {
+	return github.NewSource(client, backend)
+}
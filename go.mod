@@ -3,8 +3,12 @@ module github.com/agnivo988/Repo-lyzer
 go 1.24.4
 
 require (
+	github.com/BurntSushi/toml v1.5.0
 	github.com/charmbracelet/bubbles v0.21.0
 	github.com/spf13/cobra v1.10.2
+	github.com/spf13/pflag v1.0.9
+	go.yaml.in/yaml/v3 v3.0.4
+	golang.org/x/sync v0.16.0
 )
 
 require (
@@ -40,5 +44,4 @@ require (
 	github.com/charmbracelet/lipgloss v1.1.0
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
 	github.com/olekukonko/tablewriter v1.1.2
-	github.com/spf13/pflag v1.0.9 // indirect
 )
@@ -0,0 +1,532 @@
+// Package analysis runs the fetch-then-score pipeline shared by the CLI
+// commands and the TUI: it pulls everything it needs from a
+// github.RepoSource and turns it into a single Result.
+package analysis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/agnivo988/Repo-lyzer/internal/analyzer"
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+	"golang.org/x/sync/errgroup"
+)
+
+// Defaults applied to zero Options fields.
+const (
+	DefaultCommitDays      = 365
+	DefaultConcurrency     = 4
+	DefaultPRDetails       = 30
+	DefaultIssueDetails    = 30
+	DefaultCommitStats     = 100
+	DefaultLicenseFiles    = 10
+	DefaultSecurityFiles   = 20
+	DefaultDependencyFiles = 20
+)
+
+// Section names a part of the analysis that is fetched on its own.
+type Section string
+
+const (
+	SectionCommits      Section = "commits"
+	SectionCommitStats  Section = "commit_stats"
+	SectionHistory      Section = "history"
+	SectionContributors Section = "contributors"
+	SectionLanguages    Section = "languages"
+	SectionFileTree     Section = "file_tree"
+	SectionPullRequests Section = "pull_requests"
+	SectionIssues       Section = "issues"
+	SectionReleases     Section = "releases"
+	SectionCommunity    Section = "community"
+	SectionLicense      Section = "license"
+	SectionBranch       Section = "branch"
+	// SectionLicenseHeaders, SectionSecurityFiles and
+	// SectionDependencyFiles are fetched after the file tree, which they
+	// pick their files from.
+	SectionLicenseHeaders  Section = "license_headers"
+	SectionSecurityFiles   Section = "security_files"
+	SectionDependencyFiles Section = "dependency_files"
+)
+
+// Options tunes what Run fetches.
+type Options struct {
+	// CommitDays is the commit history window in days. 0 means
+	// DefaultCommitDays.
+	CommitDays int
+
+	// CommitStats fetches line counts and changed files for up to this
+	// many of the newest commits, which the truck factor and the bus
+	// factor are based on. 0 means DefaultCommitStats; a negative value
+	// fetches none, which leaves the truck factor out and the bus factor
+	// estimated from commit shares.
+	CommitStats int
+
+	// History also fetches the whole commit history, up to the page cap,
+	// which the retention analytics are based on. When the cap cuts it
+	// short they only count the newcomers a retention window after the
+	// cut; see analyzer.RetentionReport.NewcomersSince.
+	History bool
+
+	// PRDetails fetches the size and reviews of up to this many of the
+	// newest pull requests, which the review and size metrics are based
+	// on. 0 means DefaultPRDetails; a negative value fetches none.
+	PRDetails int
+
+	// IssueDetails fetches the comments of up to this many of the newest
+	// issues, which the first-response metric is based on. 0 means
+	// DefaultIssueDetails; a negative value fetches none.
+	IssueDetails int
+
+	// HealthProfile weighs the health signals. nil means
+	// analyzer.DefaultProfile.
+	HealthProfile *analyzer.Profile
+
+	// FileTree also fetches the file tree of the default branch.
+	FileTree bool
+
+	// LicenseFiles checks the license headers of up to this many source
+	// files of the file tree, when it is fetched. 0 means
+	// DefaultLicenseFiles; a negative value checks none.
+	LicenseFiles int
+
+	// SecurityFiles scans up to this many configuration files of the file
+	// tree for secrets, besides its workflows, when it is fetched. 0 means
+	// DefaultSecurityFiles; a negative value scans only the workflows.
+	SecurityFiles int
+
+	// DependencyFiles reads up to this many dependency manifests of the
+	// file tree, when it is fetched. 0 means DefaultDependencyFiles; a
+	// negative value reads none.
+	DependencyFiles int
+
+	// Identities tells bots apart and merges the identities of the same
+	// person for the contributor metrics. nil leaves analyzer.DefaultBots
+	// out and merges only emails used with the same GitHub login.
+	Identities *analyzer.Identities
+
+	// OSV matches the dependencies against a local advisory database, for
+	// the vulnerability check of the security and health scores. nil
+	// matches none.
+	OSV *analyzer.OSVDatabase
+
+	// Concurrency caps how many requests run at once. 0 means
+	// DefaultConcurrency.
+	Concurrency int
+}
+
+// Result is everything known about a repository after an analysis.
+type Result struct {
+	Repo    *github.Repo
+	Commits []github.Commit
+	// History is the whole commit history, when Options.History asked for
+	// it. Like the file maps below, it is left out of the JSON export.
+	History []github.Commit `json:"-"`
+	// Contributors are people, their identities merged by
+	// Options.Identities, and Bots the bots left out of them; bots are
+	// among Contributors instead when they are included.
+	Contributors  []github.Contributor
+	Bots          []github.Contributor
+	FileTree      []github.TreeEntry
+	Languages     map[string]int
+	PullRequests  []github.PullRequest
+	PRHealth      analyzer.PRHealth
+	Issues        []github.Issue
+	IssueHealth   analyzer.IssueHealth
+	Releases      []github.Release
+	Tags          []github.Tag
+	ReleaseHealth analyzer.ReleaseHealth
+	// Community is nil when GitHub has no community profile for the
+	// repository, as for private ones.
+	Community          *github.CommunityProfile
+	CommunityStandards analyzer.CommunityStandards
+	// LicenseFile is nil when the repository has no license file.
+	LicenseFile *github.LicenseFile
+	// LicenseHeaders holds the source files whose license headers were
+	// checked, by path. Like the other file maps below, it is left out of
+	// the JSON export.
+	LicenseHeaders map[string][]byte `json:"-"`
+	License        analyzer.LicenseReport
+	// Branch is the default branch. BranchProtection is nil when it is
+	// not protected or when reading its rules needs more rights.
+	Branch           *github.Branch
+	BranchProtection *github.BranchProtection
+	// SecurityFiles holds the workflows and configuration files scanned by
+	// the security checks, by path.
+	SecurityFiles map[string][]byte `json:"-"`
+	Security      analyzer.SecurityReport
+	// DependencyFiles holds the dependency manifests read, by path.
+	DependencyFiles map[string][]byte `json:"-"`
+	Dependencies    analyzer.DependencyReport
+	// Vulnerabilities is nil when no advisory database was given or the
+	// dependencies are unavailable.
+	Vulnerabilities *analyzer.VulnerabilityReport
+	HealthScore     int
+	Health          analyzer.HealthScore // breakdown of HealthScore
+	// TruckFactor is worked out from the files changed by the commits
+	// whose stats were fetched; see Options.CommitStats. BusFactor and
+	// BusRisk come from it when it found file authors, and are estimated
+	// from the contributors' shares of commits otherwise; BusMethod says
+	// which.
+	TruckFactor   analyzer.TruckFactorReport
+	Retention     analyzer.RetentionReport
+	BusFactor     int
+	BusRisk       string
+	BusMethod     string
+	MaturityScore int
+	MaturityLevel string
+	Summary       analyzer.RecruiterSummary
+
+	// Sections records how complete each fetched section and each score
+	// is. Failed sections leave their fields above empty, and scores that
+	// depend on them are not computed.
+	Sections map[Section]SectionStatus
+}
+
+// Run fetches owner/repo from source and scores it. The repository is
+// fetched first; the independent sections after it are fetched concurrently,
+// at most opts.Concurrency at a time. Only a failure to fetch the repository
+// itself, or the cancellation of ctx, is fatal: a section that fails is
+// recorded in Result.Sections and the analysis goes on without it.
+func Run(ctx context.Context, source github.RepoSource, owner, repo string, opts Options) (*Result, error) {
+	if opts.CommitDays <= 0 {
+		opts.CommitDays = DefaultCommitDays
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = DefaultConcurrency
+	}
+	if opts.PRDetails == 0 {
+		opts.PRDetails = DefaultPRDetails
+	}
+	if opts.IssueDetails == 0 {
+		opts.IssueDetails = DefaultIssueDetails
+	}
+	if opts.CommitStats == 0 {
+		opts.CommitStats = DefaultCommitStats
+	}
+	if opts.LicenseFiles == 0 {
+		opts.LicenseFiles = DefaultLicenseFiles
+	}
+	if opts.SecurityFiles == 0 {
+		opts.SecurityFiles = DefaultSecurityFiles
+	}
+	if opts.DependencyFiles == 0 {
+		opts.DependencyFiles = DefaultDependencyFiles
+	}
+
+	r, err := source.GetRepo(ctx, owner, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Result{Repo: r, Sections: make(map[Section]SectionStatus)}
+
+	var mu sync.Mutex
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(opts.Concurrency)
+
+	// fetch runs f in the group and records the status of section from the
+	// number of items f fetched and its error. Each f only writes its own
+	// Result field.
+	fetch := func(section Section, f func(ctx context.Context) (int, error)) {
+		g.Go(func() error {
+			n, err := f(gctx)
+			if err != nil && ctx.Err() != nil {
+				return ctx.Err()
+			}
+			mu.Lock()
+			res.Sections[section] = fetchStatus(n, err)
+			mu.Unlock()
+			return nil
+		})
+	}
+
+	fetch(SectionCommits, func(ctx context.Context) (int, error) {
+		commitOpts := github.LastDays(opts.CommitDays)
+		commitOpts.Stats = max(opts.CommitStats, 0)
+		commits, err := source.GetCommits(ctx, owner, repo, commitOpts)
+		res.Commits = commits
+		// Commits whose stats failed are listed all the same; the
+		// failure is on the commit stats, which the truck factor is
+		// based on.
+		err, statsErr := splitStatsError(err)
+		if commitOpts.Stats > 0 {
+			withStats := 0
+			for _, c := range commits[:min(commitOpts.Stats, len(commits))] {
+				if c.Stats != nil || c.Files != nil {
+					withStats++
+				}
+			}
+			mu.Lock()
+			res.Sections[SectionCommitStats] = fetchStatus(withStats, statsErr)
+			mu.Unlock()
+		}
+		return len(commits), err
+	})
+	if opts.History {
+		fetch(SectionHistory, func(ctx context.Context) (int, error) {
+			history, err := source.GetCommits(ctx, owner, repo, github.CommitOptions{})
+			res.History = history
+			return len(history), err
+		})
+	}
+	fetch(SectionContributors, func(ctx context.Context) (int, error) {
+		contributors, err := source.GetContributors(ctx, owner, repo)
+		res.Contributors = contributors
+		return len(contributors), err
+	})
+	fetch(SectionLanguages, func(ctx context.Context) (int, error) {
+		langs, err := source.GetLanguages(ctx, owner, repo)
+		res.Languages = langs
+		return len(langs), err
+	})
+	fetch(SectionPullRequests, func(ctx context.Context) (int, error) {
+		prs, err := source.GetPullRequests(ctx, owner, repo, github.PullRequestOptions{
+			State:   "all",
+			Details: max(opts.PRDetails, 0),
+		})
+		res.PullRequests = prs
+		return len(prs), err
+	})
+	fetch(SectionIssues, func(ctx context.Context) (int, error) {
+		issues, err := source.GetIssues(ctx, owner, repo, github.IssueOptions{
+			State:   "all",
+			Details: max(opts.IssueDetails, 0),
+		})
+		res.Issues = issues
+		return len(issues), err
+	})
+	fetch(SectionReleases, func(ctx context.Context) (int, error) {
+		// Tags stand in for releases in release health, so one failing
+		// doesn't keep the other from being fetched.
+		releases, releasesErr := source.GetReleases(ctx, owner, repo)
+		res.Releases = releases
+		tags, tagsErr := source.GetTags(ctx, owner, repo)
+		res.Tags = tags
+		return len(releases) + len(tags), errors.Join(releasesErr, tagsErr)
+	})
+	fetch(SectionCommunity, func(ctx context.Context) (int, error) {
+		profile, err := source.GetCommunityProfile(ctx, owner, repo)
+		if errors.Is(err, github.ErrNotFound) {
+			return 0, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		res.Community = profile
+		return 1, nil
+	})
+	fetch(SectionLicense, func(ctx context.Context) (int, error) {
+		file, err := source.GetLicenseFile(ctx, owner, repo)
+		if errors.Is(err, github.ErrNotFound) {
+			return 0, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		res.LicenseFile = file
+		return 1, nil
+	})
+	fetch(SectionBranch, func(ctx context.Context) (int, error) {
+		branch, err := source.GetBranch(ctx, owner, repo, r.DefaultBranch)
+		if err != nil {
+			return 0, err
+		}
+		res.Branch = branch
+		if branch.Protected {
+			// Reading the rules needs admin rights; without them the
+			// branch is only known to be protected.
+			protection, err := source.GetBranchProtection(ctx, owner, repo, r.DefaultBranch)
+			if err != nil && ctx.Err() != nil {
+				return 1, err
+			}
+			res.BranchProtection = protection
+		}
+		return 1, nil
+	})
+	if opts.FileTree {
+		fetch(SectionFileTree, func(ctx context.Context) (int, error) {
+			tree, err := source.GetFileTree(ctx, owner, repo, r.DefaultBranch)
+			res.FileTree = tree
+			return len(tree), err
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	// fetchFiles fetches the contents of paths on the default branch into
+	// files.
+	fetchFiles := func(paths []string, files map[string][]byte) func(ctx context.Context) (int, error) {
+		return func(ctx context.Context) (int, error) {
+			for _, p := range paths {
+				content, err := source.GetFileContent(ctx, owner, repo, p, r.DefaultBranch)
+				if err != nil {
+					return len(files), err
+				}
+				files[p] = content
+			}
+			return len(files), nil
+		}
+	}
+	g, gctx = errgroup.WithContext(ctx)
+	g.SetLimit(opts.Concurrency)
+	if paths := analyzer.LicenseHeaderCandidates(res.FileTree, max(opts.LicenseFiles, 0)); len(paths) > 0 {
+		res.LicenseHeaders = make(map[string][]byte)
+		fetch(SectionLicenseHeaders, fetchFiles(paths, res.LicenseHeaders))
+	}
+	if paths := analyzer.SecurityScanCandidates(res.FileTree, max(opts.SecurityFiles, 0)); len(paths) > 0 {
+		res.SecurityFiles = make(map[string][]byte)
+		fetch(SectionSecurityFiles, fetchFiles(paths, res.SecurityFiles))
+	}
+	if paths := analyzer.DependencyManifestCandidates(res.FileTree, max(opts.DependencyFiles, 0)); len(paths) > 0 {
+		res.DependencyFiles = make(map[string][]byte)
+		fetch(SectionDependencyFiles, fetchFiles(paths, res.DependencyFiles))
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	res.score(opts)
+	return res, nil
+}
+
+// score computes the scores whose inputs are available. The others keep
+// their zero value, with "Unknown" levels, rather than being scored on
+// empty data.
+func (r *Result) score(opts Options) {
+	repo := r.Repo
+	for score := range scoreInputs {
+		if score == SectionTruckFactor && opts.CommitStats <= 0 || score == SectionRetention && !opts.History {
+			continue // nothing to work it out from
+		}
+		r.Sections[score] = r.deriveStatus(score)
+	}
+
+	roster := opts.Identities.Roster(slices.Concat(r.Commits, r.History))
+	r.Contributors, r.Bots = roster.Contributors(r.Contributors)
+
+	now := time.Now()
+	if r.Available(SectionIssueHealth) {
+		r.IssueHealth = analyzer.AnalyzeIssues(r.Issues, now)
+	}
+	if r.Available(SectionCommunityStandards) {
+		r.CommunityStandards = analyzer.AnalyzeCommunity(r.FileTree, r.Community)
+	}
+	if r.Available(SectionLicensing) {
+		r.License = analyzer.AnalyzeLicense(repo, r.LicenseFile, r.LicenseHeaders)
+	}
+	if r.Available(SectionDependencies) {
+		r.Dependencies = analyzer.AnalyzeDependencies(r.DependencyFiles)
+		if opts.OSV != nil {
+			vulns := opts.OSV.Match(r.Dependencies)
+			r.Vulnerabilities = &vulns
+		}
+	}
+	if r.Available(SectionSecurity) {
+		files := make(map[string][]byte, len(r.SecurityFiles)+len(r.LicenseHeaders))
+		for _, m := range []map[string][]byte{r.LicenseHeaders, r.SecurityFiles} {
+			for p, content := range m {
+				files[p] = content
+			}
+		}
+		r.Security = analyzer.AnalyzeSecurity(analyzer.SecurityInput{
+			Tree:            r.FileTree,
+			Files:           files,
+			Branch:          r.Branch,
+			Protection:      r.BranchProtection,
+			Vulnerabilities: r.Vulnerabilities,
+		})
+	}
+	if r.Available(SectionHealth) {
+		profile := analyzer.DefaultProfile
+		if opts.HealthProfile != nil {
+			profile = *opts.HealthProfile
+		}
+		var community *analyzer.CommunityStandards
+		if r.Available(SectionCommunityStandards) {
+			community = &r.CommunityStandards
+		}
+		r.Health = analyzer.ScoreHealth(analyzer.HealthInput{
+			Repo:            repo,
+			Commits:         r.Commits,
+			Issues:          r.IssueHealth,
+			Community:       community,
+			Vulnerabilities: r.Vulnerabilities,
+		}, profile)
+		r.HealthScore = r.Health.Score
+	}
+
+	r.BusRisk = "Unknown"
+	if r.Available(SectionTruckFactor) {
+		r.TruckFactor = analyzer.TruckFactor(r.Commits, r.FileTree, roster)
+		if r.TruckFactor.Factor == 0 {
+			// The GraphQL backend, for one, never lists changed files.
+			r.Sections[SectionTruckFactor] = SectionStatus{State: StateFailed, Reason: "not computed: no changed files in the fetched commits"}
+		}
+	}
+	switch {
+	case r.TruckFactor.Factor > 0:
+		r.BusFactor, r.BusRisk, r.BusMethod = r.TruckFactor.Factor, r.TruckFactor.Risk, analyzer.BusFromFiles
+		r.Sections[SectionBusFactor] = r.Sections[SectionTruckFactor]
+	case r.Available(SectionBusFactor):
+		r.BusFactor, r.BusRisk = analyzer.BusFactor(r.Contributors)
+		r.BusMethod = analyzer.BusFromCommits
+	}
+
+	if r.Available(SectionRetention) {
+		truncated := r.Status(SectionHistory).State == StateTruncated
+		r.Retention = analyzer.AnalyzeRetention(r.History, truncated, roster, now)
+	}
+
+	if r.Available(SectionReleaseHealth) {
+		r.ReleaseHealth = analyzer.AnalyzeReleases(r.Releases, r.Tags, r.FileTree, now)
+	}
+
+	r.MaturityLevel = "Unknown"
+	if r.Available(SectionMaturity) {
+		r.MaturityScore, r.MaturityLevel = analyzer.RepoMaturityScore(
+			repo,
+			len(r.Commits),
+			len(r.Contributors),
+			r.ReleaseHealth,
+		)
+	}
+
+	if r.Available(SectionPRHealth) {
+		r.PRHealth = analyzer.AnalyzePullRequests(r.PullRequests, now)
+	}
+
+	r.Summary = analyzer.BuildRecruiterSummary(
+		repo.FullName,
+		repo.Stars,
+		repo.Forks,
+		len(r.Commits),
+		len(r.Contributors),
+		r.MaturityScore,
+		r.MaturityLevel,
+		r.BusFactor,
+		r.BusRisk,
+	)
+	r.Summary.BusMethod = r.BusMethod
+	r.Summary.Bots = len(r.Bots)
+	if !r.Available(SectionCommits) {
+		r.Summary.ActivityLevel = "Unknown"
+	}
+	r.Summary.PRHealth = "Unknown"
+	if r.Available(SectionPRHealth) {
+		r.Summary.PRHealth = r.PRHealth.Level
+	}
+	r.Summary.CommunityStandards = "Unknown"
+	if r.Available(SectionCommunityStandards) {
+		r.Summary.CommunityStandards = fmt.Sprintf("%d%%", r.CommunityStandards.Completeness)
+	}
+	r.Summary.IssueHealth = "Unknown"
+	if r.Available(SectionIssueHealth) {
+		r.Summary.IssueHealth = r.IssueHealth.Level
+	}
+}
@@ -0,0 +1,572 @@
+package analysis
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/agnivo988/Repo-lyzer/internal/analyzer"
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+	"github.com/agnivo988/Repo-lyzer/internal/github/githubtest"
+)
+
+// fakeSource is an in-memory RepoSource. Section fetches fail with the
+// error in failing, and each takes delay to complete.
+type fakeSource struct {
+	repo         *github.Repo
+	commits      []github.Commit
+	contributors []github.Contributor
+	releases     []github.Release
+	tags         []github.Tag
+	err, tagsErr error
+	historyErr   error
+	failing      map[Section]error
+	delay        time.Duration
+
+	mu                    sync.Mutex
+	inFlight, maxInFlight int
+}
+
+// section simulates a request for s, tracking how many overlap.
+func (f *fakeSource) section(s Section) error {
+	f.mu.Lock()
+	f.inFlight++
+	if f.inFlight > f.maxInFlight {
+		f.maxInFlight = f.inFlight
+	}
+	f.mu.Unlock()
+
+	time.Sleep(f.delay)
+
+	f.mu.Lock()
+	f.inFlight--
+	f.mu.Unlock()
+	return f.failing[s]
+}
+
+// soloCommits are n commits by solo, each changing main.go.
+func soloCommits(n int) []github.Commit {
+	commits := make([]github.Commit, n)
+	for i := range commits {
+		commits[i].Author = &github.User{Login: "solo"}
+		commits[i].Files = []github.CommitFile{{Filename: "main.go", Status: "modified"}}
+	}
+	return commits
+}
+
+func (f *fakeSource) GetRepo(ctx context.Context, owner, repo string) (*github.Repo, error) {
+	return f.repo, f.err
+}
+
+func (f *fakeSource) GetCommits(ctx context.Context, owner, repo string, opts github.CommitOptions) ([]github.Commit, error) {
+	if opts.Since.IsZero() && f.historyErr != nil {
+		return f.commits, f.historyErr
+	}
+	return f.commits, f.section(SectionCommits)
+}
+
+func (f *fakeSource) GetContributors(ctx context.Context, owner, repo string) ([]github.Contributor, error) {
+	return f.contributors, f.section(SectionContributors)
+}
+
+func (f *fakeSource) GetLanguages(ctx context.Context, owner, repo string) (map[string]int, error) {
+	if err := f.section(SectionLanguages); err != nil {
+		return nil, err
+	}
+	return map[string]int{"Go": 1}, nil
+}
+
+func (f *fakeSource) GetFileTree(ctx context.Context, owner, repo, branch string) ([]github.TreeEntry, error) {
+	if err := f.section(SectionFileTree); err != nil {
+		return nil, err
+	}
+	return []github.TreeEntry{{Path: "main.go", Type: "blob"}}, nil
+}
+
+func (f *fakeSource) GetIssues(ctx context.Context, owner, repo string, opts github.IssueOptions) ([]github.Issue, error) {
+	return nil, f.section(SectionIssues)
+}
+
+func (f *fakeSource) GetPullRequests(ctx context.Context, owner, repo string, opts github.PullRequestOptions) ([]github.PullRequest, error) {
+	return nil, f.section(SectionPullRequests)
+}
+
+func (f *fakeSource) GetReleases(ctx context.Context, owner, repo string) ([]github.Release, error) {
+	return f.releases, f.section(SectionReleases)
+}
+
+func (f *fakeSource) GetCommunityProfile(ctx context.Context, owner, repo string) (*github.CommunityProfile, error) {
+	return nil, f.section(SectionCommunity)
+}
+
+func (f *fakeSource) GetLicenseFile(ctx context.Context, owner, repo string) (*github.LicenseFile, error) {
+	if err := f.section(SectionLicense); err != nil {
+		return nil, err
+	}
+	return &github.LicenseFile{Path: "LICENSE", Content: []byte("MIT License")}, nil
+}
+
+func (f *fakeSource) GetFileContent(ctx context.Context, owner, repo, path, ref string) ([]byte, error) {
+	return []byte("package main"), f.section(SectionLicenseHeaders)
+}
+
+func (f *fakeSource) GetBranch(ctx context.Context, owner, repo, branch string) (*github.Branch, error) {
+	if err := f.section(SectionBranch); err != nil {
+		return nil, err
+	}
+	return &github.Branch{Name: branch}, nil
+}
+
+func (f *fakeSource) GetBranchProtection(ctx context.Context, owner, repo, branch string) (*github.BranchProtection, error) {
+	return nil, github.ErrNotFound
+}
+
+func (f *fakeSource) GetTags(ctx context.Context, owner, repo string) ([]github.Tag, error) {
+	if f.tagsErr != nil {
+		return nil, f.tagsErr
+	}
+	return f.tags, nil
+}
+
+func TestRunWithFixtures(t *testing.T) {
+	source := githubtest.NewServer(t).Client()
+
+	res, err := Run(context.Background(), source, "octo-org", "busy", Options{FileTree: true})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	if res.Repo.FullName != "octo-org/busy" || len(res.Commits) != 150 || len(res.Contributors) != 5 {
+		t.Errorf("fetched %s with %d commits and %d contributors", res.Repo.FullName, len(res.Commits), len(res.Contributors))
+	}
+	if len(res.Bots) != 1 || res.Bots[0].Login != "dependabot[bot]" || res.Summary.Bots != 1 {
+		t.Errorf("bots = %+v", res.Bots)
+	}
+	if len(res.FileTree) != 21 || len(res.Languages) != 3 {
+		t.Errorf("fetched %d tree entries and %d languages", len(res.FileTree), len(res.Languages))
+	}
+	if res.HealthScore != 98 || res.MaturityLevel != "Production-Ready" || res.MaturityScore != 100 || res.BusRisk != "Medium Risk" {
+		t.Errorf("scored health %d, maturity %s, bus risk %s", res.HealthScore, res.MaturityLevel, res.BusRisk)
+	}
+	if l := res.License; l.SPDXID != "MIT" || l.Detected != "MIT" || l.HeadersChecked != 2 || len(l.Mismatches) != 1 {
+		t.Errorf("license = %+v", l)
+	}
+	if s := res.Security; s.Score != 50 || s.Workflows != 2 || len(res.SecurityFiles) != 4 || res.BranchProtection == nil {
+		t.Errorf("security = %+v from %d files", s, len(res.SecurityFiles))
+	}
+	if d := res.Dependencies; d.Direct != 3 || d.Transitive != 1 || len(d.Overrides) != 1 || len(d.DuplicateMajors) != 1 {
+		t.Errorf("dependencies = %+v", d)
+	}
+	if res.Summary.RepoName != "octo-org/busy" || res.Summary.Stars != 1280 || res.Summary.Forks != 214 {
+		t.Errorf("summary = %+v", res.Summary)
+	}
+}
+
+func TestRunTruckFactor(t *testing.T) {
+	source := githubtest.NewServer(t).Client()
+
+	res, err := Run(context.Background(), source, "octo-org", "solo", Options{FileTree: true, CommitStats: 2})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	tf := res.TruckFactor
+	if tf.Factor != 1 || tf.Files != 1 || tf.Commits != 2 || len(tf.KeyPeople) != 1 || tf.KeyPeople[0].Author != "sam" {
+		t.Errorf("truck factor = %+v", tf)
+	}
+	if res.BusFactor != 1 || res.BusRisk != "High Risk" {
+		t.Errorf("bus factor %d (%s)", res.BusFactor, res.BusRisk)
+	}
+}
+
+func TestRunBusFactorWithoutFiles(t *testing.T) {
+	tests := []struct {
+		name        string
+		commits     []github.Commit
+		commitStats int
+		wantTruck   SectionStatus
+	}{
+		{"commits without changed files", make([]github.Commit, 3), 0, SectionStatus{State: StateFailed, Reason: "not computed: no changed files in the fetched commits"}},
+		{"no commit stats", soloCommits(3), -1, SectionStatus{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := &fakeSource{
+				repo:         &github.Repo{FullName: "fake/repo", CreatedAt: time.Now()},
+				commits:      tt.commits,
+				contributors: []github.Contributor{{Login: "solo", Commits: 3}},
+			}
+
+			res, err := Run(context.Background(), source, "fake", "repo", Options{CommitStats: tt.commitStats})
+			if err != nil {
+				t.Fatalf("Run: %v", err)
+			}
+			if status := res.Status(SectionTruckFactor); status != tt.wantTruck {
+				t.Errorf("truck factor status = %+v, want %+v", status, tt.wantTruck)
+			}
+			// The bus factor falls back on the contributors' commit shares.
+			if !res.Available(SectionBusFactor) || res.BusFactor != 1 || res.BusRisk != "High Risk" || res.BusMethod != analyzer.BusFromCommits {
+				t.Errorf("bus factor %d (%s) from %q, want 1 from commit shares", res.BusFactor, res.BusRisk, res.BusMethod)
+			}
+		})
+	}
+}
+
+func TestRunCommitStatsFail(t *testing.T) {
+	source := &fakeSource{
+		repo:         &github.Repo{FullName: "fake/repo", CreatedAt: time.Now()},
+		commits:      append(soloCommits(2), github.Commit{Author: &github.User{Login: "solo"}}),
+		contributors: []github.Contributor{{Login: "solo", Commits: 3}},
+		failing:      map[Section]error{SectionCommits: &github.StatsError{Err: github.ErrRateLimited}},
+	}
+
+	res, err := Run(context.Background(), source, "fake", "repo", Options{})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	// The commits are all there; only the truck factor misses a commit.
+	for section, want := range map[Section]State{
+		SectionCommits:     StateOK,
+		SectionHealth:      StateOK,
+		SectionCommitStats: StatePartial,
+		SectionTruckFactor: StatePartial,
+		SectionBusFactor:   StatePartial,
+	} {
+		if got := res.Status(section); got.State != want {
+			t.Errorf("%s status = %+v, want %s", section, got, want)
+		}
+	}
+	if !errors.Is(res.Status(SectionCommitStats).Err, github.ErrRateLimited) || res.BusMethod != analyzer.BusFromFiles {
+		t.Errorf("commit stats status = %+v, bus factor from %q", res.Status(SectionCommitStats), res.BusMethod)
+	}
+}
+
+func TestRunRetention(t *testing.T) {
+	source := githubtest.NewServer(t).Client()
+
+	res, err := Run(context.Background(), source, "octo-org", "busy", Options{History: true})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if !res.Available(SectionRetention) || len(res.History) != 150 {
+		t.Fatalf("retention %+v from %d commits", res.Status(SectionRetention), len(res.History))
+	}
+	var core []string
+	for _, m := range res.Retention.Core {
+		core = append(core, m.Author)
+	}
+	if r := res.Retention; r.Contributors != 5 || r.Commits != 150 || r.OneTime != 0 || strings.Join(core, " ") != "alice bob carol" {
+		t.Errorf("retention = %+v", r)
+	}
+
+	res, err = Run(context.Background(), source, "octo-org", "busy", Options{})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if _, ok := res.Sections[SectionRetention]; ok || res.History != nil {
+		t.Errorf("retention worked out without the history: %+v", res.Status(SectionRetention))
+	}
+}
+
+func TestRunRetentionTruncated(t *testing.T) {
+	// The history is cut off 300 days back, in solo's commits; sam first
+	// committed 150 days after that, and ivy in the last month.
+	commits := soloCommits(3)
+	for i := range commits {
+		commits[i].Commit.Author.Date = time.Now().AddDate(0, 0, -300+i)
+	}
+	for _, c := range []struct {
+		login string
+		days  int
+	}{{"sam", -150}, {"sam", -20}, {"ivy", -10}} {
+		commit := github.Commit{Author: &github.User{Login: c.login}}
+		commit.Commit.Author.Date = time.Now().AddDate(0, 0, c.days)
+		commits = append(commits, commit)
+	}
+	source := &fakeSource{
+		repo:         &github.Repo{FullName: "fake/repo", CreatedAt: time.Now()},
+		commits:      commits,
+		contributors: []github.Contributor{{Login: "solo", Commits: 3}},
+		historyErr:   github.ErrTruncated,
+	}
+
+	res, err := Run(context.Background(), source, "fake", "repo", Options{History: true})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if status := res.Status(SectionRetention); status.State != StateTruncated {
+		t.Errorf("retention status = %+v", status)
+	}
+	r := res.Retention
+	if !r.Truncated || r.NewcomersSince.Before(r.Since.AddDate(0, 0, 90)) {
+		t.Errorf("newcomers counted from %v for a history cut off at %v", r.NewcomersSince, r.Since)
+	}
+	if r.Contributors != 3 || r.Newcomers != 2 || r.Eligible != 1 || r.Retained != 1 || r.OneTime != 1 {
+		t.Errorf("retention = %+v", r)
+	}
+}
+
+func TestRunWithFakeSource(t *testing.T) {
+	source := &fakeSource{
+		repo:         &github.Repo{FullName: "fake/repo", Description: "fake", CreatedAt: time.Now()},
+		commits:      soloCommits(12),
+		contributors: []github.Contributor{{Login: "solo", Commits: 12}},
+	}
+
+	res, err := Run(context.Background(), source, "fake", "repo", Options{})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if res.HealthScore != 82 || res.BusFactor != 1 || res.FileTree != nil {
+		t.Errorf("health %d, bus factor %d, tree %v", res.HealthScore, res.BusFactor, res.FileTree)
+	}
+}
+
+func TestRunFailsWithoutRepo(t *testing.T) {
+	source := &fakeSource{err: github.ErrNotFound}
+
+	if _, err := Run(context.Background(), source, "fake", "repo", Options{}); !errors.Is(err, github.ErrNotFound) {
+		t.Errorf("Run error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestRunPartialFailure(t *testing.T) {
+	source := &fakeSource{
+		repo:         &github.Repo{FullName: "fake/repo", CreatedAt: time.Now()},
+		contributors: []github.Contributor{{Login: "solo", Commits: 12}},
+		failing: map[Section]error{
+			SectionCommits:  github.ErrRateLimited,
+			SectionFileTree: github.ErrNotFound,
+		},
+	}
+
+	res, err := Run(context.Background(), source, "fake", "repo", Options{FileTree: true})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	for section, want := range map[Section]bool{
+		SectionCommits:      false,
+		SectionContributors: true,
+		SectionLanguages:    true,
+		SectionFileTree:     false,
+	} {
+		if got := res.Available(section); got != want {
+			t.Errorf("Available(%s) = %v, want %v", section, got, want)
+		}
+	}
+	if status := res.Status(SectionCommits); status.State != StateFailed || !errors.Is(status.Err, github.ErrRateLimited) {
+		t.Errorf("commits status = %+v", status)
+	}
+	if len(res.Contributors) != 1 || res.Languages["Go"] != 1 {
+		t.Errorf("healthy sections were not kept: %+v %v", res.Contributors, res.Languages)
+	}
+	if res.Available(SectionHealth) || res.Available(SectionMaturity) || res.BusMethod != analyzer.BusFromCommits {
+		t.Errorf("derived sections = %+v", res.Sections)
+	}
+	if res.HealthScore != 0 || res.MaturityLevel != "Unknown" || res.Summary.ActivityLevel != "Unknown" {
+		t.Errorf("scored missing commits: health %d, maturity %s, activity %s", res.HealthScore, res.MaturityLevel, res.Summary.ActivityLevel)
+	}
+}
+
+func TestRunWithoutCommunityProfile(t *testing.T) {
+	source := &fakeSource{
+		repo:         &github.Repo{FullName: "fake/repo", CreatedAt: time.Now()},
+		commits:      soloCommits(12),
+		contributors: []github.Contributor{{Login: "solo", Commits: 12}},
+		failing:      map[Section]error{SectionCommunity: github.ErrUnauthorized},
+	}
+
+	res, err := Run(context.Background(), source, "fake", "repo", Options{})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	status := res.Status(SectionHealth)
+	if status.State != StatePartial || status.Reason != "computed without community" || !errors.Is(status.Err, github.ErrUnauthorized) {
+		t.Errorf("health status = %+v", status)
+	}
+	if res.Available(SectionCommunityStandards) {
+		t.Errorf("community standards computed without a profile")
+	}
+	for _, s := range res.Health.Signals {
+		if s.Name == "community" {
+			t.Errorf("health scored community files without them: %+v", s)
+		}
+	}
+	if len(res.Health.Signals) == 0 {
+		t.Errorf("health not scored: %+v", res.Health)
+	}
+}
+
+func TestRunReleasesAndTags(t *testing.T) {
+	release := []github.Release{{TagName: "v1.0.0"}}
+	tests := []struct {
+		name        string
+		releases    []github.Release
+		releasesErr error
+		tagsErr     error
+		wantTags    int
+		want        State
+	}{
+		{"both", release, nil, nil, 2, StateOK},
+		{"tags without releases", nil, github.ErrRateLimited, nil, 2, StatePartial},
+		{"releases without tags", release, nil, github.ErrRateLimited, 0, StatePartial},
+		{"truncated releases", release, github.ErrTruncated, nil, 2, StateTruncated},
+		{"truncated releases without tags", release, github.ErrTruncated, github.ErrUnauthorized, 0, StatePartial},
+		{"neither", nil, github.ErrRateLimited, github.ErrRateLimited, 0, StateFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := &fakeSource{
+				repo:     &github.Repo{FullName: "fake/repo", CreatedAt: time.Now()},
+				releases: tt.releases,
+				tags:     []github.Tag{{Name: "v1.0.0"}, {Name: "v0.9.0"}},
+				tagsErr:  tt.tagsErr,
+				failing:  map[Section]error{SectionReleases: tt.releasesErr},
+			}
+
+			res, err := Run(context.Background(), source, "fake", "repo", Options{})
+			if err != nil {
+				t.Fatalf("Run: %v", err)
+			}
+			if status := res.Status(SectionReleases); status.State != tt.want {
+				t.Errorf("releases status = %+v, want %s", status, tt.want)
+			}
+			if len(res.Tags) != tt.wantTags {
+				t.Errorf("fetched %d tags, want %d", len(res.Tags), tt.wantTags)
+			}
+			if got := res.Available(SectionReleaseHealth); got != (tt.want != StateFailed) {
+				t.Errorf("release health available = %v", got)
+			}
+		})
+	}
+}
+
+func TestRunSectionStatus(t *testing.T) {
+	tests := []struct {
+		name         string
+		commits      []github.Commit
+		err          error
+		wantCommits  State
+		wantHealth   State
+		wantMaturity State
+		wantBus      State
+		wantDegraded []Section
+	}{
+		{
+			name:         "complete",
+			commits:      soloCommits(3),
+			wantCommits:  StateOK,
+			wantHealth:   StateOK,
+			wantMaturity: StateOK,
+			wantBus:      StateOK,
+		},
+		{
+			name:         "truncated",
+			commits:      soloCommits(3),
+			err:          github.ErrTruncated,
+			wantCommits:  StateTruncated,
+			wantHealth:   StateTruncated,
+			wantMaturity: StateTruncated,
+			wantBus:      StateTruncated,
+			wantDegraded: []Section{SectionBusFactor, SectionCommits, SectionHealth, SectionMaturity, SectionTruckFactor},
+		},
+		{
+			name:         "partial",
+			commits:      soloCommits(3),
+			err:          github.ErrRateLimited,
+			wantCommits:  StatePartial,
+			wantHealth:   StatePartial,
+			wantMaturity: StatePartial,
+			wantBus:      StatePartial,
+			wantDegraded: []Section{SectionBusFactor, SectionCommits, SectionHealth, SectionMaturity, SectionTruckFactor},
+		},
+		{
+			name:         "failed",
+			err:          github.ErrUnauthorized,
+			wantCommits:  StateFailed,
+			wantHealth:   StateFailed,
+			wantMaturity: StateFailed,
+			wantBus:      StateOK, // estimated from the contributors
+			wantDegraded: []Section{SectionCommits, SectionHealth, SectionMaturity, SectionTruckFactor},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := &fakeSource{
+				repo:         &github.Repo{FullName: "fake/repo", CreatedAt: time.Now()},
+				commits:      tt.commits,
+				contributors: []github.Contributor{{Login: "solo", Commits: 3}},
+				failing:      map[Section]error{SectionCommits: tt.err},
+			}
+
+			res, err := Run(context.Background(), source, "fake", "repo", Options{})
+			if err != nil {
+				t.Fatalf("Run: %v", err)
+			}
+			for section, want := range map[Section]State{
+				SectionCommits:   tt.wantCommits,
+				SectionHealth:    tt.wantHealth,
+				SectionMaturity:  tt.wantMaturity,
+				SectionBusFactor: tt.wantBus,
+			} {
+				if got := res.Status(section).State; got != want {
+					t.Errorf("%s state = %q, want %q", section, got, want)
+				}
+			}
+			if got := res.Degraded(); len(got) != len(tt.wantDegraded) {
+				t.Errorf("Degraded() = %v, want %v", got, tt.wantDegraded)
+			} else {
+				for i := range got {
+					if got[i] != tt.wantDegraded[i] {
+						t.Errorf("Degraded() = %v, want %v", got, tt.wantDegraded)
+						break
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestRunBoundsConcurrency(t *testing.T) {
+	tests := []struct {
+		concurrency int
+		want        int
+	}{
+		{1, 1},
+		{2, 2},
+		{12, 10}, // only ten sections to fetch at once
+	}
+
+	for _, tt := range tests {
+		source := &fakeSource{
+			repo:  &github.Repo{FullName: "fake/repo"},
+			delay: 20 * time.Millisecond,
+		}
+		_, err := Run(context.Background(), source, "fake", "repo", Options{FileTree: true, Concurrency: tt.concurrency})
+		if err != nil {
+			t.Fatalf("Run: %v", err)
+		}
+		if source.maxInFlight != tt.want {
+			t.Errorf("concurrency %d: %d fetches overlapped, want %d", tt.concurrency, source.maxInFlight, tt.want)
+		}
+	}
+}
+
+func TestRunCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	source := &fakeSource{
+		repo:    &github.Repo{FullName: "fake/repo"},
+		failing: map[Section]error{SectionCommits: context.Canceled},
+	}
+	cancel()
+
+	if _, err := Run(ctx, source, "fake", "repo", Options{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Run error = %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,175 @@
+package analysis
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+// State is how complete the data behind a section is.
+type State string
+
+const (
+	// StateOK means the section is complete.
+	StateOK State = "ok"
+	// StateTruncated means a list stopped at a page cap or at a limit of
+	// GitHub's; what was fetched is correct but incomplete.
+	StateTruncated State = "truncated"
+	// StatePartial means a fetch failed midway and only part of the data
+	// arrived.
+	StatePartial State = "partial"
+	// StateFailed means nothing usable was fetched.
+	StateFailed State = "failed"
+)
+
+// severity orders states from best to worst.
+var severity = map[State]int{StateOK: 0, StateTruncated: 1, StatePartial: 2, StateFailed: 3}
+
+// SectionStatus is the data quality of one section of a Result.
+type SectionStatus struct {
+	State  State  `json:"state"`
+	Reason string `json:"reason,omitempty"`
+	Err    error  `json:"-"`
+}
+
+// Scores derived from the fetched sections. Their status is the worst
+// status of the sections they are computed from.
+const (
+	SectionHealth             Section = "health"
+	SectionBusFactor          Section = "bus_factor"
+	SectionTruckFactor        Section = "truck_factor"
+	SectionRetention          Section = "retention"
+	SectionMaturity           Section = "maturity"
+	SectionPRHealth           Section = "pr_health"
+	SectionIssueHealth        Section = "issue_health"
+	SectionReleaseHealth      Section = "release_health"
+	SectionCommunityStandards Section = "community_standards"
+	SectionLicensing          Section = "licensing"
+	SectionSecurity           Section = "security"
+	SectionDependencies       Section = "dependencies"
+)
+
+// scoreInputs lists the fetched sections each score depends on.
+var scoreInputs = map[Section][]Section{
+	SectionHealth:             {SectionCommits, SectionIssues},
+	SectionBusFactor:          {SectionContributors},
+	SectionTruckFactor:        {SectionCommits, SectionFileTree, SectionCommitStats},
+	SectionRetention:          {SectionHistory},
+	SectionMaturity:           {SectionCommits, SectionContributors, SectionReleases},
+	SectionPRHealth:           {SectionPullRequests},
+	SectionIssueHealth:        {SectionIssues},
+	SectionReleaseHealth:      {SectionReleases},
+	SectionCommunityStandards: {SectionFileTree, SectionCommunity},
+	SectionLicensing:          {SectionLicense, SectionLicenseHeaders},
+	SectionSecurity:           {SectionFileTree, SectionSecurityFiles},
+	SectionDependencies:       {SectionFileTree, SectionDependencyFiles},
+}
+
+// optionalInputs lists the fetched sections a score can do without: when
+// one of them failed, the score leaves out what it would have measured and
+// is partial.
+var optionalInputs = map[Section][]Section{
+	SectionHealth: {SectionFileTree, SectionCommunity},
+}
+
+// fetchStatus classifies the outcome of fetching n items with err.
+func fetchStatus(n int, err error) SectionStatus {
+	switch {
+	case err == nil:
+		return SectionStatus{State: StateOK}
+	case truncated(err):
+		return SectionStatus{State: StateTruncated, Reason: err.Error(), Err: err}
+	case n > 0:
+		return SectionStatus{State: StatePartial, Reason: err.Error(), Err: err}
+	}
+	return SectionStatus{State: StateFailed, Reason: err.Error(), Err: err}
+}
+
+// truncated reports whether err only says the data was cut short. An error
+// joining a truncation with a failure is not.
+func truncated(err error) bool {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, e := range joined.Unwrap() {
+			if !truncated(e) {
+				return false
+			}
+		}
+		return true
+	}
+	return errors.Is(err, github.ErrTruncated)
+}
+
+// splitStatsError separates the *github.StatsError GetCommits returns when
+// commit stats failed from the error listing the commits.
+func splitStatsError(err error) (listErr, statsErr error) {
+	var stats *github.StatsError
+	if !errors.As(err, &stats) {
+		return err, nil
+	}
+	var listErrs []error
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, e := range joined.Unwrap() {
+			if !errors.As(e, new(*github.StatsError)) {
+				listErrs = append(listErrs, e)
+			}
+		}
+	}
+	return errors.Join(listErrs...), stats.Err
+}
+
+// Status returns the status of section. Sections that were not fetched
+// have the zero status, with an empty State.
+func (r *Result) Status(section Section) SectionStatus {
+	return r.Sections[section]
+}
+
+// Available reports whether section has usable data: it was fetched, at
+// least in part, or computed from such data.
+func (r *Result) Available(section Section) bool {
+	state := r.Sections[section].State
+	return state != "" && state != StateFailed
+}
+
+// Degraded lists the sections whose state is anything but StateOK, in a
+// stable order.
+func (r *Result) Degraded() []Section {
+	var degraded []Section
+	for section, status := range r.Sections {
+		if status.State != StateOK {
+			degraded = append(degraded, section)
+		}
+	}
+	sort.Slice(degraded, func(i, j int) bool { return degraded[i] < degraded[j] })
+	return degraded
+}
+
+// deriveStatus computes the status of score from the sections it is based
+// on.
+func (r *Result) deriveStatus(score Section) SectionStatus {
+	status := SectionStatus{State: StateOK}
+	worsen := func(state State, reason string, err error) {
+		if severity[state] > severity[status.State] {
+			status = SectionStatus{State: state, Reason: reason, Err: err}
+		}
+	}
+	for _, input := range scoreInputs[score] {
+		in := r.Sections[input]
+		switch in.State {
+		case StateFailed:
+			worsen(StateFailed, "not computed: "+string(input)+" unavailable", in.Err)
+		default:
+			worsen(in.State, "based on "+string(in.State)+" "+string(input), in.Err)
+		}
+	}
+	for _, input := range optionalInputs[score] {
+		in := r.Sections[input]
+		switch in.State {
+		case StateFailed:
+			worsen(StatePartial, "computed without "+string(input), in.Err)
+		default:
+			worsen(in.State, "based on "+string(in.State)+" "+string(input), in.Err)
+		}
+	}
+	return status
+}
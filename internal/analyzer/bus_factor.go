@@ -2,6 +2,16 @@ package analyzer
 
 import "github.com/agnivo988/Repo-lyzer/internal/github"
 
+// The ways a bus factor is worked out: from the authors of each file, see
+// TruckFactor, or estimated from the contributors' shares of commits when
+// no changed files are known.
+const (
+	BusFromFiles   = "file ownership"
+	BusFromCommits = "commit shares"
+)
+
+// BusFactor estimates the bus factor from how much of the work the top
+// contributor did. contributors must be sorted by commits, most first.
 func BusFactor(contributors []github.Contributor) (int, string) {
 	if len(contributors) == 0 {
 		return 0, "Unknown"
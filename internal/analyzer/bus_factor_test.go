@@ -0,0 +1,37 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+func TestBusFactor(t *testing.T) {
+	tests := []struct {
+		name     string
+		commits  []int
+		want     int
+		wantRisk string
+	}{
+		{"no contributors", nil, 0, "Unknown"},
+		{"single maintainer", []int{42}, 1, "High Risk"},
+		{"dominant maintainer", []int{80, 10, 10}, 1, "High Risk"},
+		{"exactly 70 percent", []int{70, 30}, 2, "Medium Risk"},
+		{"leading maintainer", []int{50, 30, 20}, 2, "Medium Risk"},
+		{"shared ownership", []int{30, 30, 20, 20}, 3, "Low Risk"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var contributors []github.Contributor
+			for _, n := range tt.commits {
+				contributors = append(contributors, github.Contributor{Commits: n})
+			}
+
+			got, risk := BusFactor(contributors)
+			if got != tt.want || risk != tt.wantRisk {
+				t.Errorf("BusFactor = %d (%s), want %d (%s)", got, risk, tt.want, tt.wantRisk)
+			}
+		})
+	}
+}
@@ -0,0 +1,291 @@
+package analyzer
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+// Ecosystems of the dependency manifests Repo-lyzer reads.
+const (
+	EcosystemGo    = "Go"
+	EcosystemNPM   = "npm"
+	EcosystemPyPI  = "PyPI"
+	EcosystemCargo = "crates.io"
+	EcosystemMaven = "Maven"
+)
+
+// Dependency is a dependency declared in a manifest. Version is as written,
+// a range for floating dependencies; Pinned is set when it names a single
+// version, or a local path or commit that can't move.
+type Dependency struct {
+	Name    string
+	Version string
+	Direct  bool
+	Pinned  bool
+}
+
+// Override is a directive that replaces or forces the version of a
+// dependency, like a replace in go.mod or overrides in package.json.
+type Override struct {
+	Path      string
+	Directive string
+	Name      string
+	Target    string
+}
+
+// Manifest is a parsed dependency manifest. The dependencies of a lockfile
+// are its whole resolved tree, those of other manifests what they declare.
+type Manifest struct {
+	Path         string
+	Ecosystem    string
+	Lockfile     bool
+	Dependencies []Dependency
+	Overrides    []Override
+}
+
+// manifestParsers parse the manifests Repo-lyzer reads, by file name.
+var manifestParsers = map[string]func(data []byte) (*Manifest, error){
+	"go.mod":            parseGoMod,
+	"package.json":      parsePackageJSON,
+	"package-lock.json": parsePackageLock,
+	"requirements.txt":  parseRequirements,
+	"pyproject.toml":    parsePyProject,
+	"Cargo.toml":        parseCargoToml,
+	"pom.xml":           parsePom,
+}
+
+// manifestName returns the name manifestParsers knows the file at p by, or
+// "" if it isn't a manifest. requirements-dev.txt and the like are
+// requirements files too.
+func manifestName(p string) string {
+	base := path.Base(p)
+	if _, ok := manifestParsers[base]; ok {
+		return base
+	}
+	if strings.HasPrefix(base, "requirements") && path.Ext(base) == ".txt" {
+		return "requirements.txt"
+	}
+	return ""
+}
+
+// vendoredDirs hold other projects' code: their manifests say nothing about
+// the repository's own dependencies, and their headers carry their own
+// licenses.
+var vendoredDirs = map[string]bool{"node_modules": true, "vendor": true, "third_party": true, "testdata": true}
+
+// maxManifestSize is the largest file the contents API returns, and so the
+// largest manifest that can be read.
+const maxManifestSize = 1 << 20
+
+// DependencyManifestCandidates picks up to n dependency manifests of tree,
+// shallowest first, leaving out vendored code and files too large to fetch.
+func DependencyManifestCandidates(tree []github.TreeEntry, n int) []string {
+	var paths []string
+	for _, e := range tree {
+		if e.Type != "blob" || e.Size > maxManifestSize || manifestName(e.Path) == "" || vendored(e.Path) {
+			continue
+		}
+		paths = append(paths, e.Path)
+	}
+	sort.Slice(paths, func(i, j int) bool {
+		di, dj := strings.Count(paths[i], "/"), strings.Count(paths[j], "/")
+		if di != dj {
+			return di < dj
+		}
+		return paths[i] < paths[j]
+	})
+	if len(paths) > n {
+		paths = paths[:n]
+	}
+	return paths
+}
+
+func vendored(p string) bool {
+	for _, dir := range strings.Split(path.Dir(p), "/") {
+		if vendoredDirs[dir] {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseManifest parses the dependency manifest at p.
+func ParseManifest(p string, data []byte) (*Manifest, error) {
+	parse := manifestParsers[manifestName(p)]
+	if parse == nil {
+		return nil, fmt.Errorf("%s is not a known dependency manifest", p)
+	}
+	m, err := parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", p, err)
+	}
+	m.Path = p
+	for i := range m.Overrides {
+		m.Overrides[i].Path = p
+	}
+	return m, nil
+}
+
+// DuplicateMajor is a dependency required at more than one major version.
+type DuplicateMajor struct {
+	Ecosystem string
+	Name      string
+	Majors    []string
+}
+
+type DependencyReport struct {
+	Manifests []Manifest
+	// Direct counts the declared dependencies and Transitive the others
+	// that lockfiles and go.mod list. Pinned and Floating split Direct.
+	Direct     int
+	Transitive int
+	Pinned     int
+	Floating   int
+
+	Overrides       []Override
+	DuplicateMajors []DuplicateMajor
+	// Errors lists the manifests that could not be parsed.
+	Errors []string
+}
+
+// AnalyzeDependencies parses the dependency manifests in files, by path,
+// and summarizes what the repository depends on.
+func AnalyzeDependencies(files map[string][]byte) DependencyReport {
+	var r DependencyReport
+	paths := make([]string, 0, len(files))
+	for p := range files {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		m, err := ParseManifest(p, files[p])
+		if err != nil {
+			r.Errors = append(r.Errors, err.Error())
+			continue
+		}
+		r.Manifests = append(r.Manifests, *m)
+		r.Overrides = append(r.Overrides, m.Overrides...)
+	}
+
+	// A lockfile lists the direct dependencies of the manifest next to it
+	// again; only the rest count as transitive. Old package-lock.json files
+	// don't say which entries are direct, so there the first entry named
+	// like a declared dependency is taken for it.
+	declared := make(map[string]map[string]bool)
+	for _, m := range r.Manifests {
+		if m.Lockfile {
+			continue
+		}
+		dir := path.Dir(m.Path)
+		if declared[dir] == nil {
+			declared[dir] = make(map[string]bool)
+		}
+		for _, d := range m.Dependencies {
+			if d.Direct {
+				declared[dir][d.Name] = true
+			}
+		}
+	}
+
+	majors := make(map[[2]string]map[string]bool)
+	for _, m := range r.Manifests {
+		seen := make(map[string]bool)
+		if m.Lockfile {
+			for _, d := range m.Dependencies {
+				if d.Direct {
+					seen[d.Name] = true
+				}
+			}
+		}
+		for _, d := range m.Dependencies {
+			switch {
+			case m.Lockfile:
+				if d.Direct {
+					break
+				}
+				if declared[path.Dir(m.Path)][d.Name] && !seen[d.Name] {
+					seen[d.Name] = true
+					break
+				}
+				r.Transitive++
+			case !d.Direct:
+				r.Transitive++
+			case d.Pinned:
+				r.Direct++
+				r.Pinned++
+			default:
+				r.Direct++
+				r.Floating++
+			}
+
+			name, major := majorVersion(m.Ecosystem, d)
+			if major == "" {
+				continue
+			}
+			key := [2]string{m.Ecosystem, name}
+			if majors[key] == nil {
+				majors[key] = make(map[string]bool)
+			}
+			majors[key][major] = true
+		}
+	}
+	for key, set := range majors {
+		if len(set) < 2 {
+			continue
+		}
+		dup := DuplicateMajor{Ecosystem: key[0], Name: key[1]}
+		for major := range set {
+			dup.Majors = append(dup.Majors, major)
+		}
+		sort.Slice(dup.Majors, func(i, j int) bool { return lessNumeric(dup.Majors[i], dup.Majors[j]) })
+		r.DuplicateMajors = append(r.DuplicateMajors, dup)
+	}
+	sort.Slice(r.DuplicateMajors, func(i, j int) bool {
+		a, b := r.DuplicateMajors[i], r.DuplicateMajors[j]
+		if a.Ecosystem != b.Ecosystem {
+			return a.Ecosystem < b.Ecosystem
+		}
+		return a.Name < b.Name
+	})
+	return r
+}
+
+// PinnedRatio is the fraction of direct dependencies that are pinned.
+func (r DependencyReport) PinnedRatio() float64 {
+	if r.Direct == 0 {
+		return 0
+	}
+	return float64(r.Pinned) / float64(r.Direct)
+}
+
+var (
+	firstNumber = regexp.MustCompile(`\d+`)
+	goMajor     = regexp.MustCompile(`^(.+?)(/v(\d+)|\.v(\d+))$`)
+)
+
+// majorVersion returns the name d is known by regardless of its major
+// version and that major version, or "" when d names no version. In Go,
+// major versions from 2 on are part of the module path.
+func majorVersion(ecosystem string, d Dependency) (string, string) {
+	if ecosystem == EcosystemGo {
+		if m := goMajor.FindStringSubmatch(d.Name); m != nil {
+			return m[1], m[3] + m[4]
+		}
+	}
+	if strings.ContainsAny(d.Version, "/:$") {
+		return d.Name, "" // paths, URLs and unresolved properties
+	}
+	return d.Name, firstNumber.FindString(d.Version)
+}
+
+func lessNumeric(a, b string) bool {
+	if len(a) != len(b) {
+		return len(a) < len(b)
+	}
+	return a < b
+}
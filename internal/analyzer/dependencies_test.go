@@ -0,0 +1,293 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+// depsText renders dependencies as "name version" with "(indirect)" for
+// transitive and "~" for floating ones.
+func depsText(deps []Dependency) string {
+	var parts []string
+	for _, d := range deps {
+		s := d.Name + " " + d.Version
+		if !d.Direct {
+			s += " (indirect)"
+		}
+		if !d.Pinned {
+			s += " ~"
+		}
+		parts = append(parts, s)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func overridesText(overrides []Override) string {
+	var parts []string
+	for _, o := range overrides {
+		parts = append(parts, fmt.Sprintf("%s %s => %s", o.Directive, o.Name, o.Target))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func TestParseManifest(t *testing.T) {
+	tests := []struct {
+		path          string
+		content       string
+		wantDeps      string
+		wantOverrides string
+	}{
+		{
+			path: "go.mod",
+			content: `module example.com/app
+
+go 1.22
+
+require github.com/spf13/cobra v1.8.0
+
+require (
+	golang.org/x/sync v0.7.0
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace golang.org/x/sync => ../sync
+replace (
+	example.com/old v1.0.0 => example.com/new v1.2.0
+)
+`,
+			wantDeps:      "github.com/spf13/cobra v1.8.0, golang.org/x/sync v0.7.0, gopkg.in/yaml.v3 v3.0.1 (indirect)",
+			wantOverrides: "replace golang.org/x/sync => ../sync, replace example.com/old v1.0.0 => example.com/new v1.2.0",
+		},
+		{
+			path: "web/package.json",
+			content: `{
+  "name": "web",
+  "dependencies": {"react": "18.2.0", "lodash": "^4.17.21", "shared": "workspace:*"},
+  "devDependencies": {"typescript": "~5.4.0"},
+  "overrides": {"semver": "7.5.4", "foo": {"bar": "1.0.0"}},
+  "resolutions": {"minimist": "1.2.8"}
+}`,
+			wantDeps:      "lodash ^4.17.21 ~, react 18.2.0, shared workspace:*, typescript ~5.4.0 ~",
+			wantOverrides: "overrides foo => nested overrides, overrides semver => 7.5.4, resolutions minimist => 1.2.8",
+		},
+		{
+			path: "package-lock.json",
+			content: `{
+  "lockfileVersion": 3,
+  "packages": {
+    "": {"dependencies": {"debug": "^4.3.4"}, "devDependencies": {"ms": "^2.1.3"}},
+    "node_modules/debug": {"version": "4.3.4"},
+    "node_modules/ms": {"version": "2.1.3"},
+    "node_modules/debug/node_modules/ms": {"version": "2.1.2"},
+    "packages/lib": {"version": "0.1.0"},
+    "node_modules/lib": {"link": true}
+  }
+}`,
+			wantDeps: "debug 4.3.4, ms 2.1.2 (indirect), ms 2.1.3",
+		},
+		{
+			path:     "old/package-lock.json",
+			content:  `{"lockfileVersion": 1, "dependencies": {"a": {"version": "1.0.0", "dependencies": {"b": {"version": "2.0.0"}}}}}`,
+			wantDeps: "a 1.0.0 (indirect), b 2.0.0 (indirect)",
+		},
+		{
+			path: "requirements.txt",
+			content: `# direct ones
+requests==2.31.0 \
+    --hash=sha256:abc
+flask>=2.0  # web
+numpy
+-r base.txt
+pkg @ git+https://example.com/pkg@0123456789abcdef0123456789abcdef01234567
+uvicorn[standard]==0.29.*; python_version >= "3.8"
+`,
+			wantDeps: "requests ==2.31.0, flask >=2.0 ~, numpy  ~, pkg @ git+https://example.com/pkg@0123456789abcdef0123456789abcdef01234567, uvicorn ==0.29.* ~",
+		},
+		{
+			path: "requirements-lock.txt",
+			content: `certifi==2024.2.2
+    # via
+    #   -r requirements.in
+    #   requests
+requests==2.31.0
+    # via -r requirements.in
+urllib3==2.2.1
+    # via requests
+`,
+			wantDeps: "certifi ==2024.2.2, requests ==2.31.0, urllib3 ==2.2.1 (indirect)",
+		},
+		{
+			path: "pyproject.toml",
+			content: `[project]
+name = "app"
+dependencies = [
+    "httpx\u003e=0.27",   # client
+    "pydantic==2.7.1",
+]
+
+[project.optional-dependencies]
+test = ["pytest"]
+
+[tool.poetry.dependencies]
+python = "^3.11"
+rich = "13.7.1"
+click = { version = "^8.1", extras = ["color"] }
+
+[tool.poetry.group.docs.dependencies]
+mkdocs = "1.6.0"
+
+[tool.poetry.group.lint.dependencies]
+ruff = "^0.4"
+
+[tool.uv]
+override-dependencies = ["idna==3.7"]
+`,
+			wantDeps:      "httpx >=0.27 ~, pydantic ==2.7.1, pytest  ~, click ^8.1 ~, rich 13.7.1, mkdocs 1.6.0, ruff ^0.4 ~",
+			wantOverrides: "override-dependencies idna => ==3.7",
+		},
+		{
+			path: "Cargo.toml",
+			content: `[package]
+name = "app"
+version = "0.1.0"
+
+[dependencies]
+serde = { version = "1.0", features = ["derive"] }
+log = "=0.4.21"
+util = { path = "../util" }
+tokio.workspace = true
+
+[dependencies.regex]
+version = "1.10"
+
+[target.'cfg(unix)'.dependencies]
+nix.version = "0.28"
+
+[target."thumbv7em-none-eabihf.json".dependencies]
+cortex-m = "0.7"
+
+[workspace.dependencies]
+tokio = "1"
+
+[patch.crates-io]
+openssl = { git = "https://github.com/sfackler/rust-openssl", rev = "abc123" }
+`,
+			wantDeps:      "log =0.4.21, regex 1.10 ~, serde 1.0 ~, tokio workspace, util path:../util, nix 0.28 ~, cortex-m 0.7 ~",
+			wantOverrides: "patch openssl => git:https://github.com/sfackler/rust-openssl@abc123",
+		},
+		{
+			path: "pom.xml",
+			content: `<project>
+  <properties><junit.version>5.10.2</junit.version></properties>
+  <dependencyManagement><dependencies>
+    <dependency><groupId>com.fasterxml.jackson.core</groupId><artifactId>jackson-databind</artifactId><version>2.17.0</version></dependency>
+  </dependencies></dependencyManagement>
+  <dependencies>
+    <dependency><groupId>org.junit.jupiter</groupId><artifactId>junit-jupiter</artifactId><version>${junit.version}</version></dependency>
+    <dependency><groupId>com.google.guava</groupId><artifactId>guava</artifactId><version>[32.0,)</version></dependency>
+    <dependency><groupId>com.fasterxml.jackson.core</groupId><artifactId>jackson-databind</artifactId></dependency>
+  </dependencies>
+</project>`,
+			wantDeps:      "org.junit.jupiter:junit-jupiter 5.10.2, com.google.guava:guava [32.0,) ~, com.fasterxml.jackson.core:jackson-databind ",
+			wantOverrides: "dependencyManagement com.fasterxml.jackson.core:jackson-databind => 2.17.0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			m, err := ParseManifest(tt.path, []byte(tt.content))
+			if err != nil {
+				t.Fatalf("ParseManifest: %v", err)
+			}
+			if got := depsText(m.Dependencies); got != tt.wantDeps {
+				t.Errorf("dependencies:\n got %s\nwant %s", got, tt.wantDeps)
+			}
+			if got := overridesText(m.Overrides); got != tt.wantOverrides {
+				t.Errorf("overrides:\n got %s\nwant %s", got, tt.wantOverrides)
+			}
+			for _, o := range m.Overrides {
+				if o.Path != tt.path {
+					t.Errorf("override path = %q, want %q", o.Path, tt.path)
+				}
+			}
+		})
+	}
+}
+
+func TestParseManifestErrors(t *testing.T) {
+	tests := []struct {
+		path    string
+		content string
+		want    string
+	}{
+		{"go.mod", "require (\n\texample.com/x\n)\n", "parsing go.mod: line 2: malformed require"},
+		{"package.json", "{", "parsing package.json"},
+		{"Cargo.toml", "[dependencies]\nserde = \"1.0\n", "parsing Cargo.toml: toml: line 2"},
+		{"README.md", "", "not a known dependency manifest"},
+	}
+	for _, tt := range tests {
+		if _, err := ParseManifest(tt.path, []byte(tt.content)); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("ParseManifest(%s) error = %v, want %q", tt.path, err, tt.want)
+		}
+	}
+}
+
+func TestAnalyzeDependencies(t *testing.T) {
+	files := map[string][]byte{
+		"go.mod":           []byte("module m\n\nrequire (\n\tgithub.com/a/b v1.2.0\n\tgithub.com/a/b/v2 v2.0.1\n\tgithub.com/c/d v0.3.0 // indirect\n)\n\nreplace github.com/c/d => ./d\n"),
+		"web/package.json": []byte(`{"dependencies": {"debug": "^4.3.4", "react": "18.2.0"}}`),
+		"web/package-lock.json": []byte(`{"lockfileVersion": 3, "packages": {
+			"": {"dependencies": {"debug": "^4.3.4", "react": "18.2.0"}},
+			"node_modules/debug": {"version": "4.3.4"},
+			"node_modules/react": {"version": "18.2.0"},
+			"node_modules/loose-envify": {"version": "1.4.0"},
+			"node_modules/foo/node_modules/debug": {"version": "2.6.9"}
+		}}`),
+		"broken/Cargo.toml": []byte("[dependencies\n"),
+	}
+
+	r := AnalyzeDependencies(files)
+	if len(r.Manifests) != 3 || len(r.Errors) != 1 || !strings.Contains(r.Errors[0], "broken/Cargo.toml") {
+		t.Errorf("manifests %d, errors %v", len(r.Manifests), r.Errors)
+	}
+	// go.mod: 2 direct + 1 indirect; package.json: 2 direct; the lockfile:
+	// loose-envify and the nested debug.
+	if r.Direct != 4 || r.Transitive != 3 || r.Pinned != 3 || r.Floating != 1 {
+		t.Errorf("direct %d, transitive %d, pinned %d, floating %d", r.Direct, r.Transitive, r.Pinned, r.Floating)
+	}
+	if r.PinnedRatio() != 0.75 {
+		t.Errorf("pinned ratio = %v", r.PinnedRatio())
+	}
+	if got := overridesText(r.Overrides); got != "replace github.com/c/d => ./d" {
+		t.Errorf("overrides = %s", got)
+	}
+	var dups []string
+	for _, d := range r.DuplicateMajors {
+		dups = append(dups, fmt.Sprintf("%s %s %v", d.Ecosystem, d.Name, d.Majors))
+	}
+	if got, want := strings.Join(dups, ", "), "Go github.com/a/b [1 2], npm debug [2 4]"; got != want {
+		t.Errorf("duplicate majors = %s, want %s", got, want)
+	}
+}
+
+func TestDependencyManifestCandidates(t *testing.T) {
+	tree := []github.TreeEntry{
+		{Path: "web/package.json", Type: "blob"},
+		{Path: "web/node_modules/react/package.json", Type: "blob"},
+		{Path: "go.mod", Type: "blob"},
+		{Path: "web/package-lock.json", Type: "blob", Size: 2 << 20},
+		{Path: "requirements-dev.txt", Type: "blob"},
+		{Path: "vendor/github.com/x/go.mod", Type: "blob"},
+		{Path: "svc/api/pom.xml", Type: "blob"},
+		{Path: "notes.txt", Type: "blob"},
+	}
+
+	got := DependencyManifestCandidates(tree, 3)
+	want := []string{"go.mod", "requirements-dev.txt", "web/package.json"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("DependencyManifestCandidates = %v, want %v", got, want)
+	}
+}
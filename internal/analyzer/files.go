@@ -1 +1,106 @@
-package analyzer
\ No newline at end of file
+package analyzer
+
+import (
+	"path"
+	"strings"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+// communityDirs are the directories GitHub looks in for community health
+// files, besides the root.
+var communityDirs = []string{".github", "docs"}
+
+// CommunityCheck is one item of the community standards checklist. Names
+// are lower-case file names without extension; Dir names a directory whose
+// files all count.
+type CommunityCheck struct {
+	Item  string
+	Names []string
+	Dir   string
+	// FromProfile reports the item as found in a community profile.
+	FromProfile func(p *github.CommunityProfile) *github.CommunityFile
+}
+
+// CommunityChecks is the community standards checklist, in the order it is
+// reported.
+var CommunityChecks = []CommunityCheck{
+	{"README", []string{"readme"}, "", func(p *github.CommunityProfile) *github.CommunityFile { return p.Files.Readme }},
+	{"LICENSE", []string{"license", "licence", "copying"}, "", func(p *github.CommunityProfile) *github.CommunityFile { return p.Files.License }},
+	{"CONTRIBUTING", []string{"contributing"}, "", func(p *github.CommunityProfile) *github.CommunityFile { return p.Files.Contributing }},
+	{"CODE_OF_CONDUCT", []string{"code_of_conduct", "code-of-conduct"}, "", func(p *github.CommunityProfile) *github.CommunityFile { return p.Files.CodeOfConduct }},
+	{"SECURITY", []string{"security"}, "", nil},
+	{"Issue templates", []string{"issue_template"}, ".github/ISSUE_TEMPLATE", func(p *github.CommunityProfile) *github.CommunityFile { return p.Files.IssueTemplate }},
+	{"PR template", []string{"pull_request_template"}, ".github/PULL_REQUEST_TEMPLATE", func(p *github.CommunityProfile) *github.CommunityFile { return p.Files.PullRequestTemplate }},
+	{"CODEOWNERS", []string{"codeowners"}, "", nil},
+	{"FUNDING", []string{"funding"}, "", nil},
+}
+
+// CommunityItem is the outcome of one CommunityCheck. Path is the file that
+// satisfied it, or "community profile" when only GitHub's profile knew of
+// it, e.g. for files inherited from the owner's .github repository.
+type CommunityItem struct {
+	Item  string
+	Found bool
+	Path  string
+}
+
+type CommunityStandards struct {
+	Items []CommunityItem
+	Found int
+	// Completeness is the percentage of CommunityChecks found.
+	Completeness int
+}
+
+// AnalyzeCommunity checks the file tree of a repository, and its community
+// profile when there is one, against CommunityChecks.
+func AnalyzeCommunity(tree []github.TreeEntry, profile *github.CommunityProfile) CommunityStandards {
+	var s CommunityStandards
+	for _, check := range CommunityChecks {
+		item := CommunityItem{Item: check.Item}
+		if p, ok := check.find(tree); ok {
+			item.Found, item.Path = true, p
+		} else if profile != nil && check.FromProfile != nil && check.FromProfile(profile) != nil {
+			item.Found, item.Path = true, "community profile"
+		}
+		if item.Found {
+			s.Found++
+		}
+		s.Items = append(s.Items, item)
+	}
+	s.Completeness = s.Found * 100 / len(CommunityChecks)
+	return s
+}
+
+// find returns the first path of tree that satisfies c.
+func (c CommunityCheck) find(tree []github.TreeEntry) (string, bool) {
+	for _, e := range tree {
+		if c.Dir != "" && e.Type == "blob" && strings.EqualFold(path.Dir(e.Path), c.Dir) {
+			return e.Path, true
+		}
+		if e.Type != "blob" || !inCommunityDir(e.Path) {
+			continue
+		}
+		base := strings.ToLower(path.Base(e.Path))
+		base = strings.TrimSuffix(base, path.Ext(base))
+		for _, name := range c.Names {
+			if base == name {
+				return e.Path, true
+			}
+		}
+	}
+	return "", false
+}
+
+func inCommunityDir(p string) bool {
+	dir := path.Dir(p)
+	if dir == "." {
+		return true
+	}
+	for _, d := range communityDirs {
+		if strings.EqualFold(dir, d) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,69 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+func TestAnalyzeCommunity(t *testing.T) {
+	blobs := func(paths ...string) []github.TreeEntry {
+		var tree []github.TreeEntry
+		for _, p := range paths {
+			tree = append(tree, github.TreeEntry{Path: p, Type: "blob"})
+		}
+		return tree
+	}
+	inherited := &github.CommunityProfile{}
+	inherited.Files.CodeOfConduct = &github.CommunityFile{}
+	inherited.Files.Contributing = &github.CommunityFile{}
+
+	tests := []struct {
+		name      string
+		tree      []github.TreeEntry
+		profile   *github.CommunityProfile
+		wantFound map[string]string
+		wantPct   int
+	}{
+		{"empty", nil, nil, map[string]string{}, 0},
+		{
+			name: "everything in the usual places",
+			tree: blobs("README.md", "LICENSE", "CONTRIBUTING.md", ".github/CODE_OF_CONDUCT.md", "SECURITY.md",
+				".github/ISSUE_TEMPLATE/bug.yml", "docs/PULL_REQUEST_TEMPLATE.md", ".github/CODEOWNERS", ".github/FUNDING.yml"),
+			wantFound: map[string]string{
+				"README": "README.md", "LICENSE": "LICENSE", "CONTRIBUTING": "CONTRIBUTING.md",
+				"CODE_OF_CONDUCT": ".github/CODE_OF_CONDUCT.md", "SECURITY": "SECURITY.md",
+				"Issue templates": ".github/ISSUE_TEMPLATE/bug.yml", "PR template": "docs/PULL_REQUEST_TEMPLATE.md",
+				"CODEOWNERS": ".github/CODEOWNERS", "FUNDING": ".github/FUNDING.yml",
+			},
+			wantPct: 100,
+		},
+		{
+			name:    "nested files don't count, inherited ones do",
+			tree:    blobs("readme.rst", "pkg/LICENSE", "src/docs/SECURITY.md"),
+			profile: inherited,
+			wantFound: map[string]string{
+				"README": "readme.rst", "CONTRIBUTING": "community profile", "CODE_OF_CONDUCT": "community profile",
+			},
+			wantPct: 33,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := AnalyzeCommunity(tt.tree, tt.profile)
+			if len(s.Items) != len(CommunityChecks) {
+				t.Fatalf("got %d items, want %d", len(s.Items), len(CommunityChecks))
+			}
+			for _, item := range s.Items {
+				want, found := tt.wantFound[item.Item]
+				if item.Found != found || item.Path != want {
+					t.Errorf("%s = found %v at %q, want %v at %q", item.Item, item.Found, item.Path, found, want)
+				}
+			}
+			if s.Found != len(tt.wantFound) || s.Completeness != tt.wantPct {
+				t.Errorf("found %d (%d%%), want %d (%d%%)", s.Found, s.Completeness, len(tt.wantFound), tt.wantPct)
+			}
+		})
+	}
+}
@@ -1,25 +1,113 @@
 package analyzer
 
-import "github.com/agnivo988/Repo-lyzer/internal/github"
+import (
+	"fmt"
+	"math"
 
-func CalculateHealth(repo *github.Repo, commits []github.Commit) int {
-	score := 50
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
 
-	if repo.Description != "" {
-		score += 10
-	}
-	if repo.Stars > 50 {
-		score += 10
-	}
-	if len(commits) > 10 {
-		score += 20
-	}
-	if repo.OpenIssues < 20 {
-		score += 10
-	}
+// HealthInput is what the health signals measure.
+type HealthInput struct {
+	Repo    *github.Repo
+	Commits []github.Commit
+	Issues  IssueHealth
+	// Community is nil when the community standards couldn't be checked.
+	Community *CommunityStandards
+	// Vulnerabilities is nil when no advisory database was loaded.
+	Vulnerabilities *VulnerabilityReport
+}
+
+// HealthSignals are the signals a Profile can weigh, in the order the
+// breakdown lists them.
+var HealthSignals = []Signal{
+	{Name: "description", Measure: func(in HealthInput) (float64, float64, string) {
+		if in.Repo.Description == "" {
+			return 0, 0, "no description"
+		}
+		return 1, 1, "has a description"
+	}},
+	{Name: "stars", Measure: func(in HealthInput) (float64, float64, string) {
+		return threshold(in.Repo.Stars, 50, "stars")
+	}},
+	{Name: "commits", Measure: func(in HealthInput) (float64, float64, string) {
+		return threshold(len(in.Commits), 10, "recent commits")
+	}},
+	{Name: "issues", Measure: func(in HealthInput) (float64, float64, string) {
+		normalized := 0.0
+		switch in.Issues.Level {
+		case "Healthy", "No issues":
+			normalized = 1
+		case "Fair":
+			normalized = 0.5
+		}
+		return float64(in.Issues.Open), normalized,
+			fmt.Sprintf("issue handling %s, %d open", in.Issues.Level, in.Issues.Open)
+	}},
+	{
+		Name: "community",
+		Measure: func(in HealthInput) (float64, float64, string) {
+			c := in.Community
+			return float64(c.Completeness), float64(c.Completeness) / 100,
+				fmt.Sprintf("%d of %d community files", c.Found, len(CommunityChecks))
+		},
+		Applies: func(in HealthInput) bool { return in.Community != nil },
+	},
+	// vulnerabilities measures how much known vulnerabilities take away,
+	// from 0 for a clean scan to 1, adding up vulnerabilityPenalty for each
+	// one. Profiles give it a negative weight.
+	{
+		Name: "vulnerabilities",
+		Measure: func(in HealthInput) (float64, float64, string) {
+			v := in.Vulnerabilities
+			penalty := 0.0
+			for _, vuln := range v.Vulnerabilities {
+				penalty += vulnerabilityPenalty[vuln.Severity]
+			}
+			return float64(len(v.Vulnerabilities)), math.Min(1, penalty),
+				fmt.Sprintf("%d known vulnerabilities in %s", len(v.Vulnerabilities), v.checkedText())
+		},
+		Applies: func(in HealthInput) bool { return in.Vulnerabilities != nil },
+	},
+}
 
-	if score > 100 {
-		score = 100
+// vulnerabilityPenalty is the share of the vulnerabilities weight one
+// vulnerability of each severity takes away.
+var vulnerabilityPenalty = map[Severity]float64{
+	SeverityCritical: 1,
+	SeverityHigh:     0.5,
+	SeverityMedium:   0.25,
+	SeverityLow:      0.1,
+	SeverityUnknown:  0.1,
+}
+
+// threshold scores n as 1 above limit and 0 otherwise.
+func threshold(n, limit int, what string) (float64, float64, string) {
+	if n > limit {
+		return float64(n), 1, fmt.Sprintf("%d %s (more than %d)", n, what, limit)
 	}
-	return score
+	return float64(n), 0, fmt.Sprintf("%d %s (%d or fewer)", n, what, limit)
+}
+
+// DefaultProfile is the profile used unless another one is loaded. Its
+// base and positive weights add up to 100. Without the community standards
+// the other weights scale back to 10, 10, 20 and 10, the scores before
+// community files were checked. Known vulnerabilities take up to 20 points
+// off.
+var DefaultProfile = Profile{
+	Name: "default",
+	Base: 50,
+	Weights: map[string]float64{
+		"description":     8,
+		"stars":           8,
+		"commits":         16,
+		"issues":          8,
+		"community":       10,
+		"vulnerabilities": -20,
+	},
+}
+
+// CalculateHealth scores a repository with DefaultProfile.
+func CalculateHealth(repo *github.Repo, commits []github.Commit, issues IssueHealth) int {
+	return ScoreHealth(HealthInput{Repo: repo, Commits: commits, Issues: issues}, DefaultProfile).Score
 }
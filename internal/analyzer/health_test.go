@@ -0,0 +1,37 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+func TestCalculateHealth(t *testing.T) {
+	poor := IssueHealth{Total: 20, Level: "Poor"}
+
+	tests := []struct {
+		name    string
+		repo    github.Repo
+		commits int
+		issues  IssueHealth
+		want    int
+	}{
+		{"empty repository", github.Repo{}, 0, poor, 50},
+		{"no issues", github.Repo{}, 0, IssueHealth{Level: "No issues"}, 60},
+		{"fair issue handling", github.Repo{}, 0, IssueHealth{Total: 4, Level: "Fair"}, 55},
+		{"described", github.Repo{Description: "x"}, 0, poor, 60},
+		{"popular", github.Repo{Stars: 51}, 0, poor, 60},
+		{"active", github.Repo{}, 11, poor, 70},
+		{"everything", github.Repo{Description: "x", Stars: 1000}, 500, IssueHealth{Total: 3, Level: "Healthy"}, 100},
+		{"thresholds are exclusive", github.Repo{Stars: 50}, 10, poor, 50},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			commits := make([]github.Commit, tt.commits)
+			if got := CalculateHealth(&tt.repo, commits, tt.issues); got != tt.want {
+				t.Errorf("CalculateHealth = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,330 @@
+package analyzer
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+// DefaultBots are automation accounts that GitHub doesn't mark as bots and
+// whose login doesn't end in "[bot]".
+var DefaultBots = []string{
+	"dependabot",
+	"dependabot-preview",
+	"renovate",
+	"renovate-bot",
+	"greenkeeper",
+	"snyk-bot",
+	"github-actions",
+	"pre-commit-ci",
+	"imgbot",
+	"allcontributors",
+	"semantic-release-bot",
+	"mergify",
+}
+
+// Identities says which contributor identities are bots and which belong to
+// the same person. A nil *Identities knows DefaultBots and no mailmap, and
+// leaves bots out; the zero value only knows the bots GitHub marks or names
+// as such.
+type Identities struct {
+	// Bots lists more logins or git names of bots, matched ignoring case.
+	// A trailing "*" matches any rest, as in "ci-*".
+	Bots []string
+
+	// IncludeBots counts bots as contributors in every contributor metric.
+	IncludeBots bool
+
+	// mailmap maps commit identities to proper ones, keyed by email or by
+	// name and email; see mailmapKey.
+	mailmap map[string]mailmapEntry
+}
+
+// mailmapEntry is the proper name and email of an identity; either may be
+// empty to keep the commit's own.
+type mailmapEntry struct {
+	name, email string
+}
+
+// NewIdentities returns Identities that know DefaultBots and bots.
+func NewIdentities(bots ...string) *Identities {
+	return &Identities{Bots: append(append([]string(nil), DefaultBots...), bots...)}
+}
+
+var mailmapEmail = regexp.MustCompile(`<([^>]*)>`)
+
+// ParseMailmap adds the entries of a git mailmap file to ids. Each line
+// maps a commit email, or a commit name and email, to a proper name, a
+// proper email or both:
+//
+//	Proper Name <commit@email>
+//	<proper@email> <commit@email>
+//	Proper Name <proper@email> <commit@email>
+//	Proper Name <proper@email> Commit Name <commit@email>
+func (ids *Identities) ParseMailmap(data []byte) error {
+	if ids.mailmap == nil {
+		ids.mailmap = make(map[string]mailmapEntry)
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		emails := mailmapEmail.FindAllStringSubmatchIndex(line, -1)
+		if len(emails) == 0 || len(emails) > 2 || strings.TrimSpace(line[emails[len(emails)-1][1]:]) != "" {
+			return fmt.Errorf("mailmap line %d: want a name and up to two <emails>", n)
+		}
+		proper := mailmapEntry{name: strings.TrimSpace(line[:emails[0][0]])}
+		commitName, commitEmail := "", line[emails[0][2]:emails[0][3]]
+		if len(emails) == 2 {
+			proper.email = commitEmail
+			commitName = strings.TrimSpace(line[emails[0][1]:emails[1][0]])
+			commitEmail = line[emails[1][2]:emails[1][3]]
+		}
+		if commitEmail == "" {
+			return fmt.Errorf("mailmap line %d: empty commit email", n)
+		}
+		ids.mailmap[mailmapKey(commitName, commitEmail)] = proper
+	}
+	return scanner.Err()
+}
+
+// LoadMailmap adds the entries of the mailmap file at path to ids.
+func (ids *Identities) LoadMailmap(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading mailmap: %w", err)
+	}
+	if err := ids.ParseMailmap(data); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+func mailmapKey(name, email string) string {
+	key := strings.ToLower(email)
+	if name != "" {
+		key = strings.ToLower(name) + "\x00" + key
+	}
+	return key
+}
+
+// resolve returns the proper name and email of a commit identity: the
+// mailmap entry for both name and email, or else for the email alone.
+func (ids *Identities) resolve(name, email string) (string, string) {
+	if ids == nil || email == "" {
+		return name, email
+	}
+	proper, ok := ids.mailmap[mailmapKey(name, email)]
+	if !ok {
+		proper = ids.mailmap[mailmapKey("", email)]
+	}
+	if proper.name != "" {
+		name = proper.name
+	}
+	if proper.email != "" {
+		email = proper.email
+	}
+	return name, email
+}
+
+// IsBot reports whether an account or git identity is a bot: GitHub says
+// its account type is Bot, its login or name ends in "[bot]", or it is in
+// the bot list.
+func (ids *Identities) IsBot(login, name, accountType string) bool {
+	if accountType == "Bot" {
+		return true
+	}
+	bots := DefaultBots
+	if ids != nil {
+		bots = ids.Bots
+	}
+	for _, id := range []string{login, name} {
+		id = strings.ToLower(id)
+		if id == "" {
+			continue
+		}
+		if strings.HasSuffix(id, "[bot]") {
+			return true
+		}
+		for _, bot := range bots {
+			bot = strings.ToLower(bot)
+			if prefix, ok := strings.CutSuffix(bot, "*"); ok && strings.HasPrefix(id, prefix) || id == bot {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (ids *Identities) includeBots() bool {
+	return ids != nil && ids.IncludeBots
+}
+
+// noreplyEmail matches the private emails GitHub commits with for a login.
+var noreplyEmail = regexp.MustCompile(`^(?:\d+\+)?([^@]+)@users\.noreply\.github\.com$`)
+
+// Roster resolves the authors of a commit history to people. Identities
+// are the same person when the mailmap maps them to the same email, or
+// when an email was used with a GitHub login, GitHub's noreply emails
+// included. Each person goes by the login they made the most commits
+// with, else by their email.
+type Roster struct {
+	ids    *Identities
+	parent map[string]string // union-find over "login:" and "email:" keys
+	shown  map[string]string // key as first seen, before lower-casing
+	names  map[string]string // person name by root key
+}
+
+// Roster works out who the authors of commits are.
+func (ids *Identities) Roster(commits []github.Commit) *Roster {
+	r := &Roster{ids: ids, parent: make(map[string]string), shown: make(map[string]string)}
+	commitsBy := make(map[string]int)
+	for i := range commits {
+		c := &commits[i]
+		keys := r.keys(c.AuthorLogin(), c.Commit.Author.Name, c.Commit.Author.Email)
+		for _, k := range keys[1:] {
+			r.union(keys[0], k)
+		}
+		commitsBy[keys[0]]++
+	}
+
+	r.names = make(map[string]string)
+	best := make(map[string]string)
+	for k := range r.parent {
+		root := r.find(k)
+		b, ok := best[root]
+		if !ok || betterName(k, b, commitsBy) {
+			best[root] = k
+		}
+	}
+	for root, k := range best {
+		r.names[root] = r.shown[k]
+	}
+	return r
+}
+
+// betterName reports whether key a names a person better than key b:
+// logins before emails, then more commits, then alphabetically.
+func betterName(a, b string, commits map[string]int) bool {
+	aLogin, bLogin := strings.HasPrefix(a, "login:"), strings.HasPrefix(b, "login:")
+	if aLogin != bLogin {
+		return aLogin
+	}
+	if commits[a] != commits[b] {
+		return commits[a] > commits[b]
+	}
+	return a < b
+}
+
+// keys returns the union-find keys of an identity, the login first.
+func (r *Roster) keys(login, name, email string) []string {
+	name, email = r.ids.resolve(name, email)
+	email = strings.ToLower(email)
+	if m := noreplyEmail.FindStringSubmatch(email); m != nil && login == "" {
+		login = m[1]
+	}
+	var keys []string
+	add := func(kind, id string) {
+		k := kind + ":" + strings.ToLower(id)
+		if _, ok := r.parent[k]; !ok {
+			r.parent[k] = k
+			r.shown[k] = id
+		}
+		keys = append(keys, k)
+	}
+	if login != "" {
+		add("login", login)
+	}
+	switch {
+	case email != "":
+		add("email", email)
+	case login == "":
+		add("name", name)
+	}
+	return keys
+}
+
+func (r *Roster) find(k string) string {
+	for r.parent[k] != k {
+		r.parent[k] = r.parent[r.parent[k]]
+		k = r.parent[k]
+	}
+	return k
+}
+
+func (r *Roster) union(a, b string) {
+	ra, rb := r.find(a), r.find(b)
+	if ra != rb {
+		r.parent[rb] = ra
+	}
+}
+
+// person names the person behind an identity.
+func (r *Roster) person(login, name, email string) string {
+	root := r.find(r.keys(login, name, email)[0])
+	if n, ok := r.names[root]; ok {
+		return n
+	}
+	return r.shown[root]
+}
+
+// Author names the person who authored c.
+func (r *Roster) Author(c *github.Commit) string {
+	return r.person(c.AuthorLogin(), c.Commit.Author.Name, c.Commit.Author.Email)
+}
+
+// Skip reports whether c was authored by a bot that is left out of the
+// contributor metrics.
+func (r *Roster) Skip(c *github.Commit) bool {
+	if r.ids.includeBots() {
+		return false
+	}
+	accountType := ""
+	if c.Author != nil {
+		accountType = c.Author.Type
+	}
+	return r.ids.IsBot(c.AuthorLogin(), c.Commit.Author.Name, accountType)
+}
+
+// Contributors merges the contributors that are the same person, adding up
+// their commits under the person's name, and sets the bots apart unless
+// they are included. Both lists are sorted by commits, most first.
+func (r *Roster) Contributors(contributors []github.Contributor) (people, bots []github.Contributor) {
+	merged := make(map[string]int)
+	var order []string
+	for _, c := range contributors {
+		if !r.ids.includeBots() && r.ids.IsBot(c.Login, "", c.Type) {
+			bots = append(bots, c)
+			continue
+		}
+		name := r.person(c.Login, "", "")
+		if _, ok := merged[name]; !ok {
+			order = append(order, name)
+			people = append(people, github.Contributor{Login: name, Type: c.Type})
+		}
+		merged[name] += c.Commits
+	}
+	for i := range people {
+		people[i].Commits = merged[order[i]]
+	}
+	sortContributors(people)
+	sortContributors(bots)
+	return people, bots
+}
+
+func sortContributors(cs []github.Contributor) {
+	sort.SliceStable(cs, func(i, j int) bool {
+		if cs[i].Commits != cs[j].Commits {
+			return cs[i].Commits > cs[j].Commits
+		}
+		return cs[i].Login < cs[j].Login
+	})
+}
@@ -0,0 +1,171 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+func TestParseMailmap(t *testing.T) {
+	const mailmap = `# people
+Alice Doe <alice@example.com>
+<bob@example.com> <bob@old.example.com>
+Carol <carol@example.com> <c@laptop.local>
+Dave <dave@example.com> dave <shared@example.com>  # only dave's commits
+`
+	ids := NewIdentities()
+	if err := ids.ParseMailmap([]byte(mailmap)); err != nil {
+		t.Fatalf("ParseMailmap: %v", err)
+	}
+
+	tests := []struct {
+		name, email string
+		want        string
+	}{
+		{"alice", "ALICE@example.com", "Alice Doe <ALICE@example.com>"},
+		{"Bob", "bob@old.example.com", "Bob <bob@example.com>"},
+		{"carol", "c@laptop.local", "Carol <carol@example.com>"},
+		{"Dave", "shared@example.com", "Dave <dave@example.com>"},
+		{"erin", "shared@example.com", "erin <shared@example.com>"},
+		{"frank", "frank@example.com", "frank <frank@example.com>"},
+	}
+	for _, tt := range tests {
+		name, email := ids.resolve(tt.name, tt.email)
+		if got := fmt.Sprintf("%s <%s>", name, email); got != tt.want {
+			t.Errorf("resolve(%s, %s) = %s, want %s", tt.name, tt.email, got, tt.want)
+		}
+	}
+}
+
+func TestParseMailmapErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		mailmap string
+		wantErr string
+	}{
+		{"no email", "Alice Doe\n", "line 1"},
+		{"three emails", "\n<a@x> <b@x> <c@x>\n", "line 2"},
+		{"trailing text", "<a@x> <b@x> Alice\n", "line 1"},
+		{"empty commit email", "Alice <>\n", "empty commit email"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewIdentities().ParseMailmap([]byte(tt.mailmap))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestIdentitiesIsBot(t *testing.T) {
+	tests := []struct {
+		ids                      *Identities
+		login, name, accountType string
+		want                     bool
+	}{
+		{nil, "dependabot[bot]", "", "Bot", true},
+		{nil, "", "github-actions[bot]", "", true},
+		{nil, "renovate-bot", "", "User", true},
+		{nil, "Renovate", "", "", true},
+		{nil, "alice", "Alice", "User", false},
+		{NewIdentities("ci-*"), "ci-runner", "", "User", true},
+		{NewIdentities("ci-*"), "", "ci-", "", true},
+		{NewIdentities("release-bot"), "", "Release-Bot", "", true},
+		{NewIdentities("release-bot"), "release-botany", "", "", false},
+		{&Identities{}, "renovate-bot", "", "User", false},
+	}
+	for _, tt := range tests {
+		if got := tt.ids.IsBot(tt.login, tt.name, tt.accountType); got != tt.want {
+			t.Errorf("IsBot(%q, %q, %q) = %v, want %v", tt.login, tt.name, tt.accountType, got, tt.want)
+		}
+	}
+}
+
+// authoredBy is a commit by a git identity, linked to login unless it is "".
+func authoredBy(login, name, email string) github.Commit {
+	var c github.Commit
+	if login != "" {
+		c.Author = &github.User{Login: login, Type: "User"}
+	}
+	c.Commit.Author = github.CommitIdentity{Name: name, Email: email}
+	return c
+}
+
+func TestRoster(t *testing.T) {
+	commits := []github.Commit{
+		authoredBy("alice", "Alice", "alice@example.com"),
+		authoredBy("alice", "Alice", "alice@example.com"),
+		authoredBy("", "Alice", "alice@example.com"),
+		authoredBy("alice-work", "Alice", "alice@corp.example"),
+		authoredBy("", "Bob", "1234+Bob@users.noreply.github.com"),
+		authoredBy("", "Carol", "carol@example.com"),
+		authoredBy("", "dependabot[bot]", "49699333+dependabot[bot]@users.noreply.github.com"),
+	}
+	contributors := []github.Contributor{
+		{Login: "alice", Type: "User", Commits: 30},
+		{Login: "dependabot[bot]", Type: "Bot", Commits: 25},
+		{Login: "bob", Type: "User", Commits: 20},
+		{Login: "alice-work", Type: "User", Commits: 12},
+		{Login: "renovate-bot", Type: "User", Commits: 4},
+	}
+	ids := NewIdentities()
+	if err := ids.ParseMailmap([]byte("<alice@example.com> <alice@corp.example>\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		includeBots bool
+		wantAuthors []string
+		wantPeople  string
+		wantBots    string
+	}{
+		{
+			name:        "bots left out",
+			wantAuthors: []string{"alice", "alice", "alice", "alice", "bob", "carol@example.com", "skip"},
+			wantPeople:  "alice 42, bob 20",
+			wantBots:    "dependabot[bot] 25, renovate-bot 4",
+		},
+		{
+			name:        "bots included",
+			includeBots: true,
+			wantAuthors: []string{"alice", "alice", "alice", "alice", "bob", "carol@example.com", "dependabot[bot]"},
+			wantPeople:  "alice 42, dependabot[bot] 25, bob 20, renovate-bot 4",
+		},
+	}
+
+	list := func(cs []github.Contributor) string {
+		var s []string
+		for _, c := range cs {
+			s = append(s, fmt.Sprintf("%s %d", c.Login, c.Commits))
+		}
+		return strings.Join(s, ", ")
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ids.IncludeBots = tt.includeBots
+			roster := ids.Roster(commits)
+			var authors []string
+			for i := range commits {
+				if roster.Skip(&commits[i]) {
+					authors = append(authors, "skip")
+					continue
+				}
+				authors = append(authors, roster.Author(&commits[i]))
+			}
+			if strings.Join(authors, " ") != strings.Join(tt.wantAuthors, " ") {
+				t.Errorf("authors = %v, want %v", authors, tt.wantAuthors)
+			}
+			people, bots := roster.Contributors(contributors)
+			if got := list(people); got != tt.wantPeople {
+				t.Errorf("people = %s, want %s", got, tt.wantPeople)
+			}
+			if got := list(bots); got != tt.wantBots {
+				t.Errorf("bots = %s, want %s", got, tt.wantBots)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package analyzer
+
+import (
+	"sort"
+	"time"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+// IssueAgeBuckets are the backlog age classes of open issues, by time since
+// they were opened, in increasing order. An issue falls in the first bucket
+// whose Max it doesn't exceed.
+var IssueAgeBuckets = []struct {
+	Label string
+	Max   time.Duration // 0 for no limit
+}{
+	{"<1w", 7 * 24 * time.Hour},
+	{"<1m", 30 * 24 * time.Hour},
+	{"<3m", 90 * 24 * time.Hour},
+	{"<1y", 365 * 24 * time.Hour},
+	{">1y", 0},
+}
+
+type IssueHealth struct {
+	Total  int
+	Open   int
+	Closed int
+	// Stale counts the open issues without activity for StaleAfter.
+	Stale int
+
+	// Medians are zero when nothing was measured; Responded counts the
+	// issues with a response the first-response median is based on.
+	MedianTimeToFirstResponse time.Duration
+	MedianTimeToClose         time.Duration
+	Responded                 int
+
+	// BacklogAge counts the open issues per IssueAgeBuckets label.
+	BacklogAge map[string]int
+
+	// Labels counts the issues carrying each label, most used first.
+	Labels []LabelCount
+
+	Level string // Healthy, Fair, Poor, or No issues
+}
+
+// LabelCount is how many issues carry a label.
+type LabelCount struct {
+	Name  string
+	Count int
+}
+
+// AnalyzeIssues computes the issue health of a repository from its issues as
+// of now.
+func AnalyzeIssues(issues []github.Issue, now time.Time) IssueHealth {
+	h := IssueHealth{Total: len(issues), BacklogAge: make(map[string]int)}
+
+	var toRespond, toClose []time.Duration
+	labels := make(map[string]int)
+	for i := range issues {
+		issue := &issues[i]
+		if issue.State == "closed" {
+			h.Closed++
+			if issue.ClosedAt != nil {
+				toClose = append(toClose, issue.ClosedAt.Sub(issue.CreatedAt))
+			}
+		} else {
+			h.Open++
+			h.BacklogAge[issueAge(now.Sub(issue.CreatedAt))]++
+			if now.Sub(issue.UpdatedAt) > StaleAfter {
+				h.Stale++
+			}
+		}
+
+		if at, ok := issue.FirstResponseAt(); ok {
+			toRespond = append(toRespond, at.Sub(issue.CreatedAt))
+		}
+		for _, l := range issue.Labels {
+			labels[l.Name]++
+		}
+	}
+
+	for name, n := range labels {
+		h.Labels = append(h.Labels, LabelCount{name, n})
+	}
+	sort.Slice(h.Labels, func(i, j int) bool {
+		if h.Labels[i].Count != h.Labels[j].Count {
+			return h.Labels[i].Count > h.Labels[j].Count
+		}
+		return h.Labels[i].Name < h.Labels[j].Name
+	})
+
+	h.MedianTimeToFirstResponse = median(toRespond)
+	h.MedianTimeToClose = median(toClose)
+	h.Responded = len(toRespond)
+	h.Level = issueHealthLevel(h)
+	return h
+}
+
+func issueAge(age time.Duration) string {
+	for _, b := range IssueAgeBuckets {
+		if b.Max == 0 || age <= b.Max {
+			return b.Label
+		}
+	}
+	return IssueAgeBuckets[len(IssueAgeBuckets)-1].Label
+}
+
+// issueHealthLevel grades h by how many of these hold: at least half the
+// issues are closed, few open ones are stale, the first response comes
+// within three days and closing takes at most a month.
+func issueHealthLevel(h IssueHealth) string {
+	if h.Total == 0 {
+		return "No issues"
+	}
+
+	points := 0
+	if float64(h.Closed) >= 0.5*float64(h.Total) {
+		points++
+	}
+	if h.Open == 0 || float64(h.Stale) <= 0.25*float64(h.Open) {
+		points++
+	}
+	if h.Responded > 0 && h.MedianTimeToFirstResponse <= 3*24*time.Hour {
+		points++
+	}
+	if h.Closed > 0 && h.MedianTimeToClose <= 30*24*time.Hour {
+		points++
+	}
+
+	switch {
+	case points >= 3:
+		return "Healthy"
+	case points == 2:
+		return "Fair"
+	}
+	return "Poor"
+}
@@ -0,0 +1,113 @@
+package analyzer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+func TestAnalyzeIssues(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	author := &github.User{Login: "author"}
+	issue := func(age time.Duration, closedAfter, respondAfter time.Duration, labels ...string) github.Issue {
+		created := now.Add(-age)
+		i := github.Issue{State: "open", User: author, CreatedAt: created, UpdatedAt: created, HasDetails: true}
+		if closedAfter > 0 {
+			closed := created.Add(closedAfter)
+			i.State, i.ClosedAt, i.UpdatedAt = "closed", &closed, closed
+		}
+		if respondAfter > 0 {
+			i.Thread = []github.Comment{
+				{User: author, CreatedAt: created.Add(respondAfter / 4)},
+				{User: &github.User{Login: "renovate[bot]"}, CreatedAt: created.Add(respondAfter / 2)},
+				{User: &github.User{Login: "maintainer"}, CreatedAt: created.Add(respondAfter)},
+			}
+		}
+		for _, l := range labels {
+			i.Labels = append(i.Labels, github.Label{Name: l})
+		}
+		return i
+	}
+
+	tests := []struct {
+		name         string
+		issues       []github.Issue
+		wantOpen     int
+		wantStale    int
+		wantResponse time.Duration
+		wantClose    time.Duration
+		wantAge      map[string]int
+		wantLabels   []LabelCount
+		wantLevel    string
+	}{
+		{
+			name:      "no issues",
+			wantAge:   map[string]int{},
+			wantLevel: "No issues",
+		},
+		{
+			name: "responsive",
+			issues: []github.Issue{
+				issue(40*day, 2*day, time.Hour, "bug"),
+				issue(30*day, 4*day, 3*time.Hour, "bug", "ui"),
+				issue(20*day, 6*day, 5*time.Hour, "enhancement"),
+				issue(2*day, 0, 0, "bug"),
+			},
+			wantOpen:     1,
+			wantResponse: 3 * time.Hour,
+			wantClose:    4 * day,
+			wantAge:      map[string]int{"<1w": 1},
+			wantLabels:   []LabelCount{{"bug", 3}, {"enhancement", 1}, {"ui", 1}},
+			wantLevel:    "Healthy",
+		},
+		{
+			name: "neglected backlog",
+			issues: []github.Issue{
+				issue(400*day, 0, 0),
+				issue(200*day, 0, 0),
+				issue(60*day, 0, 10*day),
+				issue(100*day, 50*day, 0),
+			},
+			wantOpen:     3,
+			wantStale:    2,
+			wantResponse: 10 * day,
+			wantClose:    50 * day,
+			wantAge:      map[string]int{">1y": 1, "<1y": 1, "<3m": 1},
+			wantLevel:    "Poor",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := AnalyzeIssues(tt.issues, now)
+
+			if h.Open != tt.wantOpen || h.Stale != tt.wantStale {
+				t.Errorf("open %d, stale %d; want %d, %d", h.Open, h.Stale, tt.wantOpen, tt.wantStale)
+			}
+			if h.MedianTimeToFirstResponse != tt.wantResponse || h.MedianTimeToClose != tt.wantClose {
+				t.Errorf("median response %v, close %v; want %v, %v", h.MedianTimeToFirstResponse, h.MedianTimeToClose, tt.wantResponse, tt.wantClose)
+			}
+			if len(h.BacklogAge) != len(tt.wantAge) {
+				t.Errorf("backlog age = %v, want %v", h.BacklogAge, tt.wantAge)
+			}
+			for label, n := range tt.wantAge {
+				if h.BacklogAge[label] != n {
+					t.Errorf("backlog age = %v, want %v", h.BacklogAge, tt.wantAge)
+				}
+			}
+			if len(h.Labels) != len(tt.wantLabels) {
+				t.Fatalf("labels = %v, want %v", h.Labels, tt.wantLabels)
+			}
+			for i := range h.Labels {
+				if h.Labels[i] != tt.wantLabels[i] {
+					t.Errorf("labels = %v, want %v", h.Labels, tt.wantLabels)
+				}
+			}
+			if h.Level != tt.wantLevel {
+				t.Errorf("level = %q, want %q", h.Level, tt.wantLevel)
+			}
+		})
+	}
+}
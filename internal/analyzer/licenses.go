@@ -0,0 +1,293 @@
+package analyzer
+
+import (
+	"embed"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+// License categories, from least to most restrictive for adopters.
+const (
+	LicensePermissive     = "permissive"
+	LicenseWeakCopyleft   = "weak copyleft"
+	LicenseStrongCopyleft = "strong copyleft"
+	LicenseUnknown        = "unknown"
+)
+
+// licenseCategories maps SPDX IDs, without -only or -or-later, to their
+// category.
+var licenseCategories = map[string]string{
+	"MIT": LicensePermissive, "Apache-2.0": LicensePermissive, "BSD-2-Clause": LicensePermissive,
+	"BSD-3-Clause": LicensePermissive, "BSD": LicensePermissive, "ISC": LicensePermissive,
+	"0BSD": LicensePermissive, "Unlicense": LicensePermissive, "CC0-1.0": LicensePermissive,
+	"Zlib": LicensePermissive, "BSL-1.0": LicensePermissive,
+	"LGPL-2.1": LicenseWeakCopyleft, "LGPL-3.0": LicenseWeakCopyleft, "MPL-2.0": LicenseWeakCopyleft,
+	"EPL-1.0": LicenseWeakCopyleft, "EPL-2.0": LicenseWeakCopyleft, "CDDL-1.0": LicenseWeakCopyleft,
+	"GPL-2.0": LicenseStrongCopyleft, "GPL-3.0": LicenseStrongCopyleft, "AGPL-3.0": LicenseStrongCopyleft,
+}
+
+// LicenseCategory returns the category of the license with SPDX ID id.
+func LicenseCategory(id string) string {
+	if c, ok := licenseCategories[baseLicense(id)]; ok {
+		return c
+	}
+	return LicenseUnknown
+}
+
+// baseLicense drops the -only, -or-later and + suffixes of id, which don't
+// change its category or what it is compatible with.
+func baseLicense(id string) string {
+	for _, suffix := range []string{"-only", "-or-later", "+"} {
+		id = strings.TrimSuffix(id, suffix)
+	}
+	return id
+}
+
+// licenseTexts are the license texts local detection matches against, one
+// file per SPDX ID.
+//
+//go:embed licenses/*.txt
+var licenseTexts embed.FS
+
+// licenseMatchThreshold is the least similarity to a bundled text that
+// counts as a match.
+const licenseMatchThreshold = 0.75
+
+type licenseTemplate struct {
+	id      string
+	bigrams map[string]bool
+}
+
+var licenseTemplates = func() []licenseTemplate {
+	entries, _ := licenseTexts.ReadDir("licenses")
+	var templates []licenseTemplate
+	for _, e := range entries {
+		text, _ := licenseTexts.ReadFile("licenses/" + e.Name())
+		templates = append(templates, licenseTemplate{
+			id:      strings.TrimSuffix(e.Name(), ".txt"),
+			bigrams: bigrams(string(text)),
+		})
+	}
+	return templates
+}()
+
+// DetectLicense matches a license text against the bundled license texts
+// and returns the SPDX ID of the closest with its similarity in 0..1, or ""
+// when none is close enough.
+func DetectLicense(text []byte) (string, float64) {
+	words := bigrams(string(text))
+	best, score := "", 0.0
+	for _, t := range licenseTemplates {
+		if s := dice(words, t.bigrams); s > score {
+			best, score = t.id, s
+		}
+	}
+	if score < licenseMatchThreshold {
+		return "", score
+	}
+	return best, score
+}
+
+// bigrams returns the pairs of consecutive words of text, lower-cased and
+// without punctuation. Copyright lines are left out since they differ in
+// every copy of a license.
+func bigrams(text string) map[string]bool {
+	var words []string
+	for _, line := range strings.Split(strings.ToLower(text), "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "copyright") {
+			continue
+		}
+		words = append(words, strings.FieldsFunc(line, func(r rune) bool {
+			return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+		})...)
+	}
+	set := make(map[string]bool)
+	for i := 1; i < len(words); i++ {
+		set[words[i-1]+" "+words[i]] = true
+	}
+	return set
+}
+
+// dice is the Dice coefficient of two sets.
+func dice(a, b map[string]bool) float64 {
+	if len(a)+len(b) == 0 {
+		return 0
+	}
+	common := 0
+	for k := range a {
+		if b[k] {
+			common++
+		}
+	}
+	return 2 * float64(common) / float64(len(a)+len(b))
+}
+
+var (
+	spdxHeader = regexp.MustCompile(`SPDX-License-Identifier:\s*([A-Za-z0-9.+-]+)`)
+
+	// headerPhrases recognize license notices without an SPDX tag. The
+	// first match wins, so the more specific phrases come first.
+	headerPhrases = []struct {
+		re *regexp.Regexp
+		id string
+	}{
+		{regexp.MustCompile(`(?i)Apache License,?\s+Version 2\.0`), "Apache-2.0"},
+		{regexp.MustCompile(`(?i)GNU Affero General Public License`), "AGPL-3.0"},
+		{regexp.MustCompile(`(?i)GNU Lesser General Public License[\s\S]{0,200}?version 2\.1`), "LGPL-2.1"},
+		{regexp.MustCompile(`(?i)GNU Lesser General Public License`), "LGPL-3.0"},
+		{regexp.MustCompile(`(?i)GNU General Public License[\s\S]{0,200}?version 2`), "GPL-2.0"},
+		{regexp.MustCompile(`(?i)GNU General Public License`), "GPL-3.0"},
+		{regexp.MustCompile(`(?i)Mozilla Public License,?\s+v(ersion|\.)\s*2\.0`), "MPL-2.0"},
+		{regexp.MustCompile(`(?i)\bMIT License\b|Permission is hereby granted, free of charge`), "MIT"},
+		{regexp.MustCompile(`(?i)\bBSD[- ]style\b|\bBSD license`), "BSD"},
+	}
+)
+
+// headerBytes is how much of a source file is searched for a license notice.
+const headerBytes = 2048
+
+// HeaderLicense returns the license a source file's header notice names,
+// as an SPDX ID, or "" when it has none. A BSD notice without the number of
+// clauses is "BSD".
+func HeaderLicense(src []byte) string {
+	if len(src) > headerBytes {
+		src = src[:headerBytes]
+	}
+	if m := spdxHeader.FindSubmatch(src); m != nil {
+		return string(m[1])
+	}
+	for _, p := range headerPhrases {
+		if p.re.Match(src) {
+			return p.id
+		}
+	}
+	return ""
+}
+
+// sameLicense reports whether a header naming header agrees with the
+// repository license id.
+func sameLicense(header, id string) bool {
+	header, id = baseLicense(header), baseLicense(id)
+	if strings.EqualFold(header, id) {
+		return true
+	}
+	return header == "BSD" && strings.HasPrefix(id, "BSD-")
+}
+
+// sourceExtensions are the files whose headers are checked for license
+// notices.
+var sourceExtensions = map[string]bool{
+	".go": true, ".py": true, ".js": true, ".ts": true, ".java": true, ".kt": true, ".c": true,
+	".h": true, ".cc": true, ".cpp": true, ".rs": true, ".rb": true, ".php": true, ".cs": true,
+	".swift": true, ".scala": true, ".sh": true,
+}
+
+// LicenseHeaderCandidates picks up to n source files of tree to check the
+// license headers of, leaving out vendored code. It spreads them over the
+// directories, taking the first file of each in path order before the
+// second of any, so that one large package doesn't fill the sample.
+func LicenseHeaderCandidates(tree []github.TreeEntry, n int) []string {
+	byDir := make(map[string][]string)
+	for _, e := range tree {
+		if e.Type == "blob" && sourceExtensions[strings.ToLower(path.Ext(e.Path))] && !vendored(e.Path) {
+			dir := path.Dir(e.Path)
+			byDir[dir] = append(byDir[dir], e.Path)
+		}
+	}
+	dirs := sortedKeys(byDir)
+	for _, dir := range dirs {
+		sort.Strings(byDir[dir])
+	}
+
+	var paths []string
+	for i := 0; len(paths) < n; i++ {
+		added := false
+		for _, dir := range dirs {
+			if i < len(byDir[dir]) && len(paths) < n {
+				paths = append(paths, byDir[dir][i])
+				added = true
+			}
+		}
+		if !added {
+			break
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// HeaderMismatch is a source file whose license notice names another
+// license than the repository's.
+type HeaderMismatch struct {
+	Path    string
+	License string
+}
+
+type LicenseReport struct {
+	// SPDXID is the license of the repository: the one GitHub reports, or
+	// else the one detected locally. It is "" when neither knows.
+	SPDXID   string
+	Category string
+
+	// FromAPI is the SPDX ID GitHub reports. Detected is the closest
+	// bundled license text to File, with its similarity as Confidence.
+	FromAPI    string
+	Detected   string
+	Confidence float64
+	File       string
+
+	HeadersChecked int
+	Mismatches     []HeaderMismatch
+}
+
+// AnalyzeLicense works out the license of repo from what GitHub reports and
+// from its license file, which is nil when it has none, and checks the
+// source file headers against it.
+func AnalyzeLicense(repo *github.Repo, file *github.LicenseFile, headers map[string][]byte) LicenseReport {
+	var r LicenseReport
+	if l := repo.License; l != nil && l.SPDXID != "" && l.SPDXID != "NOASSERTION" {
+		r.FromAPI = l.SPDXID
+	}
+	if file != nil {
+		r.File = file.Path
+		r.Detected, r.Confidence = DetectLicense(file.Content)
+		if r.FromAPI == "" && file.License != nil && file.License.SPDXID != "NOASSERTION" {
+			r.FromAPI = file.License.SPDXID
+		}
+	}
+
+	r.SPDXID = r.FromAPI
+	if r.SPDXID == "" {
+		r.SPDXID = r.Detected
+	}
+	r.Category = LicenseCategory(r.SPDXID)
+
+	for p, src := range headers {
+		r.HeadersChecked++
+		header := HeaderLicense(src)
+		if header != "" && r.SPDXID != "" && !sameLicense(header, r.SPDXID) {
+			r.Mismatches = append(r.Mismatches, HeaderMismatch{Path: p, License: header})
+		}
+	}
+	sort.Slice(r.Mismatches, func(i, j int) bool { return r.Mismatches[i].Path < r.Mismatches[j].Path })
+	return r
+}
+
+// Allowed reports whether the license passes policy, a list of SPDX IDs and
+// categories. Unknown licenses never pass.
+func (r LicenseReport) Allowed(policy []string) bool {
+	if r.SPDXID == "" {
+		return false
+	}
+	for _, p := range policy {
+		p = strings.TrimSpace(p)
+		if sameLicense(r.SPDXID, p) || strings.EqualFold(strings.ReplaceAll(p, "-", " "), r.Category) {
+			return true
+		}
+	}
+	return false
+}
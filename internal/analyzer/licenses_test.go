@@ -0,0 +1,230 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+const mitText = `MIT License
+
+Copyright (c) 2024 Octo Org
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+`
+
+func TestDetectLicense(t *testing.T) {
+	bundled := func(id string) string {
+		text, err := licenseTexts.ReadFile("licenses/" + id + ".txt")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(text)
+	}
+	bsd3 := bundled("BSD-3-Clause")
+
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"mit with its own copyright", mitText, "MIT"},
+		{"reflowed", strings.Join(strings.Fields(mitText), " "), "MIT"},
+		{"bsd 3 clause", strings.ReplaceAll(bsd3, "<copyright holder>", "Octo Org"), "BSD-3-Clause"},
+		{"bsd 2 clause", bundled("BSD-2-Clause"), "BSD-2-Clause"},
+		{"apache", bundled("Apache-2.0"), "Apache-2.0"},
+		{"lgpl 2.1", bundled("LGPL-2.1"), "LGPL-2.1"},
+		{"gpl 3", bundled("GPL-3.0"), "GPL-3.0"},
+		{"not a license", "This project is proprietary. All rights reserved.", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, confidence := DetectLicense([]byte(tt.text))
+			if got != tt.want {
+				t.Errorf("DetectLicense = %q (%.2f), want %q", got, confidence, tt.want)
+			}
+		})
+	}
+}
+
+func TestLicenseCategory(t *testing.T) {
+	tests := map[string]string{
+		"MIT":              LicensePermissive,
+		"BSD-3-Clause":     LicensePermissive,
+		"MPL-2.0":          LicenseWeakCopyleft,
+		"LGPL-2.1-only":    LicenseWeakCopyleft,
+		"GPL-3.0-or-later": LicenseStrongCopyleft,
+		"AGPL-3.0":         LicenseStrongCopyleft,
+		"WTFPL":            LicenseUnknown,
+		"":                 LicenseUnknown,
+	}
+	for id, want := range tests {
+		if got := LicenseCategory(id); got != want {
+			t.Errorf("LicenseCategory(%q) = %q, want %q", id, got, want)
+		}
+	}
+}
+
+func TestHeaderLicense(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"spdx tag", "// SPDX-License-Identifier: GPL-3.0-or-later\npackage main\n", "GPL-3.0-or-later"},
+		{"apache notice", "/*\n * Licensed under the Apache License, Version 2.0 (the \"License\");\n */", "Apache-2.0"},
+		{"go style bsd", "// Use of this source code is governed by a BSD-style\n// license that can be found in the LICENSE file.", "BSD"},
+		{"gpl 2", "# under the terms of the GNU General Public License as published by\n# the Free Software Foundation; either version 2 of the License", "GPL-2.0"},
+		{"lgpl", "GNU Lesser General Public License", "LGPL-3.0"},
+		{"no notice", "package main\n\nfunc main() {}\n", ""},
+		{"past the header", "package main\n" + strings.Repeat("\n", headerBytes) + "// SPDX-License-Identifier: MIT\n", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HeaderLicense([]byte(tt.src)); got != tt.want {
+				t.Errorf("HeaderLicense = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyzeLicense(t *testing.T) {
+	headers := map[string][]byte{
+		"main.go":       []byte("// SPDX-License-Identifier: MIT\npackage main"),
+		"util.go":       []byte("package main"),
+		"vendor/gpl.go": []byte("// SPDX-License-Identifier: GPL-2.0-only\npackage gpl"),
+		"lib/apache.go": []byte("// Licensed under the Apache License, Version 2.0\npackage lib"),
+	}
+
+	tests := []struct {
+		name           string
+		repo           *github.Repo
+		file           *github.LicenseFile
+		headers        map[string][]byte
+		wantID         string
+		wantDetected   string
+		wantCategory   string
+		wantMismatches []string
+	}{
+		{
+			name:         "reported and detected",
+			repo:         &github.Repo{License: &github.License{Key: "mit", SPDXID: "MIT"}},
+			file:         &github.LicenseFile{Path: "LICENSE", Content: []byte(mitText)},
+			headers:      headers,
+			wantID:       "MIT",
+			wantDetected: "MIT",
+			wantCategory: LicensePermissive,
+			wantMismatches: []string{
+				"lib/apache.go: Apache-2.0", "vendor/gpl.go: GPL-2.0-only",
+			},
+		},
+		{
+			name:         "unrecognized by github",
+			repo:         &github.Repo{License: &github.License{Key: "other", SPDXID: "NOASSERTION"}},
+			file:         &github.LicenseFile{Path: "COPYING", Content: []byte(mitText)},
+			wantID:       "MIT",
+			wantDetected: "MIT",
+			wantCategory: LicensePermissive,
+		},
+		{
+			name:         "no license",
+			repo:         &github.Repo{},
+			headers:      headers,
+			wantCategory: LicenseUnknown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := AnalyzeLicense(tt.repo, tt.file, tt.headers)
+			if r.SPDXID != tt.wantID || r.Detected != tt.wantDetected || r.Category != tt.wantCategory {
+				t.Errorf("report = %+v", r)
+			}
+			if r.HeadersChecked != len(tt.headers) {
+				t.Errorf("checked %d headers, want %d", r.HeadersChecked, len(tt.headers))
+			}
+			var got []string
+			for _, m := range r.Mismatches {
+				got = append(got, m.Path+": "+m.License)
+			}
+			if strings.Join(got, ", ") != strings.Join(tt.wantMismatches, ", ") {
+				t.Errorf("mismatches = %v, want %v", got, tt.wantMismatches)
+			}
+		})
+	}
+}
+
+func TestLicenseHeaderCandidates(t *testing.T) {
+	tree := []github.TreeEntry{
+		{Path: "main.go", Type: "blob"},
+		{Path: "README.md", Type: "blob"},
+		{Path: "api/a.go", Type: "blob"},
+		{Path: "api/b.go", Type: "blob"},
+		{Path: "api/c.go", Type: "blob"},
+		{Path: "api/d.go", Type: "blob"},
+		{Path: "cli/run.go", Type: "blob"},
+		{Path: "cli/flags.go", Type: "blob"},
+		{Path: "vendor/example.com/x/x.go", Type: "blob"},
+		{Path: "web/node_modules/left-pad/index.js", Type: "blob"},
+		{Path: "cli", Type: "tree"},
+	}
+
+	tests := []struct {
+		n    int
+		want string
+	}{
+		{2, "api/a.go main.go"},
+		{4, "api/a.go api/b.go cli/flags.go main.go"},
+		{5, "api/a.go api/b.go cli/flags.go cli/run.go main.go"},
+		{10, "api/a.go api/b.go api/c.go api/d.go cli/flags.go cli/run.go main.go"},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(LicenseHeaderCandidates(tree, tt.n), " "); got != tt.want {
+			t.Errorf("LicenseHeaderCandidates(%d) = %s, want %s", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestLicenseAllowed(t *testing.T) {
+	mit := LicenseReport{SPDXID: "MIT", Category: LicensePermissive}
+	gpl := LicenseReport{SPDXID: "GPL-3.0-only", Category: LicenseStrongCopyleft}
+
+	tests := []struct {
+		name   string
+		report LicenseReport
+		policy []string
+		want   bool
+	}{
+		{"by id", mit, []string{"Apache-2.0", "mit"}, true},
+		{"by category", mit, []string{"permissive"}, true},
+		{"hyphenated category", gpl, []string{"weak-copyleft", "strong-copyleft"}, true},
+		{"suffix ignored", gpl, []string{"GPL-3.0"}, true},
+		{"not listed", gpl, []string{"permissive", "MPL-2.0"}, false},
+		{"unknown never passes", LicenseReport{Category: LicenseUnknown}, []string{"unknown"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.report.Allowed(tt.policy); got != tt.want {
+				t.Errorf("Allowed(%v) = %v, want %v", tt.policy, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,442 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// parseGoMod reads the require and replace directives of a go.mod file.
+// Go builds with exactly the versions listed, so every dependency is
+// pinned; those marked // indirect are transitive.
+func parseGoMod(data []byte) (*Manifest, error) {
+	m := &Manifest{Ecosystem: EcosystemGo}
+	block := ""
+	for n, line := range strings.Split(string(data), "\n") {
+		comment := ""
+		if i := strings.Index(line, "//"); i >= 0 {
+			line, comment = line[:i], strings.TrimSpace(line[i+2:])
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		directive := block
+		switch {
+		case block != "" && fields[0] == ")":
+			block = ""
+			continue
+		case block == "" && len(fields) == 2 && fields[1] == "(":
+			block = fields[0]
+			continue
+		case block == "":
+			directive, fields = fields[0], fields[1:]
+		}
+
+		switch directive {
+		case "require":
+			if len(fields) < 2 {
+				return nil, fmt.Errorf("line %d: malformed require", n+1)
+			}
+			m.Dependencies = append(m.Dependencies, Dependency{
+				Name:    strings.Trim(fields[0], `"`),
+				Version: fields[1],
+				Direct:  !strings.HasPrefix(comment, "indirect"),
+				Pinned:  true,
+			})
+		case "replace":
+			arrow := -1
+			for i, f := range fields {
+				if f == "=>" {
+					arrow = i
+				}
+			}
+			if arrow < 1 || arrow == len(fields)-1 {
+				return nil, fmt.Errorf("line %d: malformed replace", n+1)
+			}
+			m.Overrides = append(m.Overrides, Override{
+				Directive: "replace",
+				Name:      strings.Join(fields[:arrow], " "),
+				Target:    strings.Join(fields[arrow+1:], " "),
+			})
+		}
+	}
+	return m, nil
+}
+
+var exactSemver = regexp.MustCompile(`^=?v?\d+\.\d+\.\d+([-+][0-9A-Za-z.+-]*)?$`)
+
+// npmPinned reports whether an npm version spec names a single version.
+func npmPinned(spec string) bool {
+	spec = strings.TrimSpace(spec)
+	for _, local := range []string{"file:", "link:", "workspace:"} {
+		if strings.HasPrefix(spec, local) {
+			return true
+		}
+	}
+	return exactSemver.MatchString(spec)
+}
+
+func parsePackageJSON(data []byte) (*Manifest, error) {
+	var pkg struct {
+		Dependencies         map[string]string      `json:"dependencies"`
+		DevDependencies      map[string]string      `json:"devDependencies"`
+		OptionalDependencies map[string]string      `json:"optionalDependencies"`
+		Overrides            map[string]interface{} `json:"overrides"`
+		Resolutions          map[string]string      `json:"resolutions"`
+		PNPM                 struct {
+			Overrides map[string]string `json:"overrides"`
+		} `json:"pnpm"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return nil, err
+	}
+
+	m := &Manifest{Ecosystem: EcosystemNPM}
+	for _, deps := range []map[string]string{pkg.Dependencies, pkg.DevDependencies, pkg.OptionalDependencies} {
+		for _, name := range sortedKeys(deps) {
+			m.Dependencies = append(m.Dependencies, Dependency{Name: name, Version: deps[name], Direct: true, Pinned: npmPinned(deps[name])})
+		}
+	}
+	for _, name := range sortedKeys(pkg.Overrides) {
+		target := "nested overrides"
+		if v, ok := pkg.Overrides[name].(string); ok {
+			target = v
+		}
+		m.Overrides = append(m.Overrides, Override{Directive: "overrides", Name: name, Target: target})
+	}
+	for _, name := range sortedKeys(pkg.Resolutions) {
+		m.Overrides = append(m.Overrides, Override{Directive: "resolutions", Name: name, Target: pkg.Resolutions[name]})
+	}
+	for _, name := range sortedKeys(pkg.PNPM.Overrides) {
+		m.Overrides = append(m.Overrides, Override{Directive: "pnpm.overrides", Name: name, Target: pkg.PNPM.Overrides[name]})
+	}
+	return m, nil
+}
+
+// parsePackageLock reads the resolved tree of a package-lock.json, from
+// its packages (lockfile version 2 and 3) or its nested dependencies
+// (version 1).
+func parsePackageLock(data []byte) (*Manifest, error) {
+	type lockDep struct {
+		Version      string             `json:"version"`
+		Dependencies map[string]lockDep `json:"dependencies"`
+	}
+	var lock struct {
+		Packages map[string]struct {
+			Version         string            `json:"version"`
+			Link            bool              `json:"link"`
+			Dependencies    map[string]string `json:"dependencies"`
+			DevDependencies map[string]string `json:"devDependencies"`
+		} `json:"packages"`
+		Dependencies map[string]lockDep `json:"dependencies"`
+	}
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, err
+	}
+
+	m := &Manifest{Ecosystem: EcosystemNPM, Lockfile: true}
+	if lock.Packages != nil {
+		root := lock.Packages[""]
+		for _, key := range sortedKeys(lock.Packages) {
+			i := strings.LastIndex(key, "node_modules/")
+			pkg := lock.Packages[key]
+			if i < 0 || pkg.Link {
+				continue // the root and workspace packages
+			}
+			name := key[i+len("node_modules/"):]
+			_, dep := root.Dependencies[name]
+			_, dev := root.DevDependencies[name]
+			m.Dependencies = append(m.Dependencies, Dependency{
+				Name:    name,
+				Version: pkg.Version,
+				Direct:  (dep || dev) && i == 0,
+				Pinned:  true,
+			})
+		}
+		return m, nil
+	}
+
+	var walk func(deps map[string]lockDep)
+	walk = func(deps map[string]lockDep) {
+		for _, name := range sortedKeys(deps) {
+			m.Dependencies = append(m.Dependencies, Dependency{Name: name, Version: deps[name].Version, Pinned: true})
+			walk(deps[name].Dependencies)
+		}
+	}
+	walk(lock.Dependencies)
+	return m, nil
+}
+
+var (
+	pep508    = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9._-]*)\s*(\[[^\]]*\])?\s*(.*)$`)
+	commitRef = regexp.MustCompile(`@[0-9a-f]{40}\b`)
+)
+
+// parseRequirement parses a PEP 508 requirement like "requests[socks]>=2.0;
+// python_version < '3.12'".
+func parseRequirement(spec string) (Dependency, bool) {
+	if i := strings.Index(spec, ";"); i >= 0 {
+		spec = spec[:i]
+	}
+	m := pep508.FindStringSubmatch(strings.TrimSpace(spec))
+	if m == nil {
+		return Dependency{}, false
+	}
+	version := strings.Trim(strings.TrimSpace(m[3]), "()")
+	var pinned bool
+	if strings.HasPrefix(version, "@") {
+		// Direct references pin when they name a commit.
+		pinned = commitRef.MatchString(version)
+	} else {
+		pinned = strings.HasPrefix(version, "==") && !strings.ContainsAny(version, "*,")
+	}
+	return Dependency{Name: m[1], Version: version, Direct: true, Pinned: pinned}, true
+}
+
+// parseRequirements reads a pip requirements file. Files compiled by
+// pip-compile say where each requirement comes from in "# via" comments
+// below it; requirements only pulled in by other packages are transitive.
+func parseRequirements(data []byte) (*Manifest, error) {
+	m := &Manifest{Ecosystem: EcosystemPyPI}
+	last, via, declared := -1, false, false
+	done := func() {
+		if last >= 0 && via && !declared {
+			m.Dependencies[last].Direct = false
+		}
+		last, via, declared = -1, false, false
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "#") {
+			note := strings.TrimSpace(strings.TrimPrefix(line, "#"))
+			if last >= 0 && (via || strings.HasPrefix(note, "via")) {
+				via = true
+				declared = declared || strings.Contains(note, "-r ")
+			}
+			continue
+		}
+		if i := strings.Index(line, " #"); i >= 0 {
+			line = strings.TrimSpace(line[:i])
+		}
+		line = strings.TrimSpace(strings.TrimSuffix(line, "\\"))
+		if line == "" || strings.HasPrefix(line, "-") {
+			continue // pip options, and the hashes of the last requirement
+		}
+		d, ok := parseRequirement(line)
+		if !ok {
+			continue
+		}
+		done()
+		m.Dependencies = append(m.Dependencies, d)
+		last = len(m.Dependencies) - 1
+	}
+	done()
+	return m, nil
+}
+
+// parsePyProject reads the PEP 621, PEP 735 and Poetry dependencies of a
+// pyproject.toml, those of every Poetry group included, and uv's
+// override-dependencies.
+func parsePyProject(data []byte) (*Manifest, error) {
+	doc, err := parseTOML(data)
+	if err != nil {
+		return nil, err
+	}
+	m := &Manifest{Ecosystem: EcosystemPyPI}
+	addSpecs := func(list interface{}) {
+		specs, _ := list.([]interface{})
+		for _, s := range specs {
+			if spec, ok := s.(string); ok {
+				if d, ok := parseRequirement(spec); ok {
+					m.Dependencies = append(m.Dependencies, d)
+				}
+			}
+		}
+	}
+	project := tomlTable(doc, "project")
+	addSpecs(project["dependencies"])
+	groups := tomlTable(project, "optional-dependencies")
+	for _, group := range sortedKeys(groups) {
+		addSpecs(groups[group])
+	}
+	groups = tomlTable(doc, "dependency-groups")
+	for _, group := range sortedKeys(groups) {
+		addSpecs(groups[group])
+	}
+
+	poetry := tomlTable(doc, "tool", "poetry")
+	tables := []map[string]interface{}{tomlTable(poetry, "dependencies"), tomlTable(poetry, "dev-dependencies")}
+	groups = tomlTable(poetry, "group")
+	for _, group := range sortedKeys(groups) {
+		tables = append(tables, tomlTable(groups, group, "dependencies"))
+	}
+	for _, deps := range tables {
+		for _, name := range sortedKeys(deps) {
+			if name == "python" {
+				continue
+			}
+			version, pinned := tomlVersion(deps[name])
+			// Poetry reads a bare version as that exact version.
+			pinned = pinned || exactSemver.MatchString(version) || strings.HasPrefix(version, "==")
+			m.Dependencies = append(m.Dependencies, Dependency{Name: name, Version: version, Direct: true, Pinned: pinned})
+		}
+	}
+
+	overrides, _ := tomlTable(doc, "tool", "uv")["override-dependencies"].([]interface{})
+	for _, o := range overrides {
+		if spec, ok := o.(string); ok {
+			if d, ok := parseRequirement(spec); ok {
+				m.Overrides = append(m.Overrides, Override{Directive: "override-dependencies", Name: d.Name, Target: d.Version})
+			}
+		}
+	}
+	return m, nil
+}
+
+// tomlVersion returns the version of a dependency written as a version
+// string or as a table, and whether the table pins it to a path, a commit
+// or the workspace's version.
+func tomlVersion(v interface{}) (string, bool) {
+	switch v := v.(type) {
+	case string:
+		return v, false
+	case map[string]interface{}:
+		if version, ok := v["version"].(string); ok {
+			return version, false
+		}
+		if p, ok := v["path"].(string); ok {
+			return "path:" + p, true
+		}
+		if git, ok := v["git"].(string); ok {
+			rev, _ := v["rev"].(string)
+			if rev == "" {
+				rev, _ = v["tag"].(string)
+			}
+			return "git:" + git + "@" + rev, rev != ""
+		}
+		if ws, _ := v["workspace"].(bool); ws {
+			return "workspace", true
+		}
+	}
+	return "", false
+}
+
+// cargoDependencyTables are the tables that list dependencies, at the top
+// level or under a [target.'cfg(unix)'] table. [workspace.dependencies] only
+// sets versions for members to inherit.
+var cargoDependencyTables = []string{"dependencies", "dev-dependencies", "build-dependencies"}
+
+// parseCargoToml reads the dependencies of a Cargo.toml, including
+// target-specific ones, and its [patch] and [replace] sections. Cargo
+// reads a bare version as a caret range, so only "=" versions are pinned.
+func parseCargoToml(data []byte) (*Manifest, error) {
+	doc, err := parseTOML(data)
+	if err != nil {
+		return nil, err
+	}
+	m := &Manifest{Ecosystem: EcosystemCargo}
+	addTables := func(parent map[string]interface{}) {
+		for _, table := range cargoDependencyTables {
+			deps := tomlTable(parent, table)
+			for _, name := range sortedKeys(deps) {
+				spec := deps[name]
+				version, pinned := tomlVersion(spec)
+				if t, ok := spec.(map[string]interface{}); ok {
+					if pkg, ok := t["package"].(string); ok {
+						name = pkg // renamed dependency
+					}
+				}
+				pinned = pinned || strings.HasPrefix(strings.TrimSpace(version), "=")
+				m.Dependencies = append(m.Dependencies, Dependency{Name: name, Version: version, Direct: true, Pinned: pinned})
+			}
+		}
+	}
+	addTables(doc)
+	targets := tomlTable(doc, "target")
+	for _, target := range sortedKeys(targets) {
+		addTables(tomlTable(targets, target))
+	}
+
+	addOverrides := func(directive string, patches map[string]interface{}) {
+		for _, name := range sortedKeys(patches) {
+			target, _ := tomlVersion(patches[name])
+			m.Overrides = append(m.Overrides, Override{Directive: directive, Name: name, Target: target})
+		}
+	}
+	registries := tomlTable(doc, "patch")
+	for _, registry := range sortedKeys(registries) {
+		addOverrides("patch", tomlTable(registries, registry))
+	}
+	addOverrides("replace", tomlTable(doc, "replace"))
+	return m, nil
+}
+
+type pomDependency struct {
+	GroupID    string `xml:"groupId"`
+	ArtifactID string `xml:"artifactId"`
+	Version    string `xml:"version"`
+}
+
+// parsePom reads the dependencies of a Maven pom.xml, resolving versions
+// from its properties. Versions left to a parent or to
+// dependencyManagement are managed, and count as pinned; dependencyManagement
+// entries are reported as overrides since they force the versions of
+// transitive dependencies too.
+func parsePom(data []byte) (*Manifest, error) {
+	var pom struct {
+		Properties struct {
+			Entries []struct {
+				XMLName xml.Name
+				Value   string `xml:",chardata"`
+			} `xml:",any"`
+		} `xml:"properties"`
+		Dependencies []pomDependency `xml:"dependencies>dependency"`
+		Management   []pomDependency `xml:"dependencyManagement>dependencies>dependency"`
+	}
+	if err := xml.Unmarshal(data, &pom); err != nil {
+		return nil, err
+	}
+	props := make(map[string]string)
+	for _, p := range pom.Properties.Entries {
+		props[p.XMLName.Local] = strings.TrimSpace(p.Value)
+	}
+	resolve := func(v string) string {
+		v = strings.TrimSpace(v)
+		if strings.HasPrefix(v, "${") && strings.HasSuffix(v, "}") {
+			if resolved, ok := props[v[2:len(v)-1]]; ok {
+				return resolved
+			}
+		}
+		return v
+	}
+
+	m := &Manifest{Ecosystem: EcosystemMaven}
+	for _, d := range pom.Dependencies {
+		version := resolve(d.Version)
+		floating := strings.ContainsAny(version, "[(,") || version == "LATEST" || version == "RELEASE" ||
+			strings.HasSuffix(version, "-SNAPSHOT") || strings.HasPrefix(version, "${")
+		m.Dependencies = append(m.Dependencies, Dependency{
+			Name:    d.GroupID + ":" + d.ArtifactID,
+			Version: version,
+			Direct:  true,
+			Pinned:  !floating,
+		})
+	}
+	for _, d := range pom.Management {
+		m.Overrides = append(m.Overrides, Override{Directive: "dependencyManagement", Name: d.GroupID + ":" + d.ArtifactID, Target: resolve(d.Version)})
+	}
+	return m, nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
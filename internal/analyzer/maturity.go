@@ -6,7 +6,7 @@ import (
 	"github.com/agnivo988/Repo-lyzer/internal/github"
 )
 
-func RepoMaturityScore(repo *github.Repo, commits int, contributors int, hasReleases bool) (int, string) {
+func RepoMaturityScore(repo *github.Repo, commits int, contributors int, releases ReleaseHealth) (int, string) {
 	score := 0
 
 	// Age
@@ -25,9 +25,19 @@ func RepoMaturityScore(repo *github.Repo, commits int, contributors int, hasRele
 		score += 20
 	}
 
-	// Releases
-	if hasReleases {
-		score += 20
+	// Releases: versioned at all, with semantic versions, with documented
+	// changes, still released at the usual pace
+	if releases.HasReleases() {
+		score += 5
+		if releases.SemverRatio >= 0.8 {
+			score += 5
+		}
+		if releases.HasChangelog() {
+			score += 5
+		}
+		if releases.Current() {
+			score += 5
+		}
 	}
 
 	// Issues sanity
@@ -0,0 +1,50 @@
+package analyzer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+func TestRepoMaturityScore(t *testing.T) {
+	day := 24 * time.Hour
+	old := time.Now().AddDate(-3, 0, 0)
+	recent := time.Now().AddDate(0, -2, 0)
+	none := ReleaseHealth{}
+	tagged := ReleaseHealth{Tags: 4, SemverRatio: 0.5}
+	released := ReleaseHealth{Releases: 6, SemverRatio: 1, WithNotes: 2, SinceLastRelease: 30 * day, Cadence: 60 * day}
+	stale := released
+	stale.SinceLastRelease = 2 * 365 * day
+	yearly := released
+	yearly.SinceLastRelease, yearly.Cadence = 300*day, 365*day
+
+	tests := []struct {
+		name         string
+		repo         github.Repo
+		commits      int
+		contributors int
+		releases     ReleaseHealth
+		want         int
+		wantLevel    string
+	}{
+		{"brand new", github.Repo{CreatedAt: recent, OpenIssues: 60}, 0, 1, none, 0, "Prototype"},
+		{"new but tidy", github.Repo{CreatedAt: recent}, 5, 1, none, 15, "Prototype"},
+		{"growing", github.Repo{CreatedAt: old}, 20, 2, none, 55, "Growing"},
+		{"stable", github.Repo{CreatedAt: old, OpenIssues: 80}, 101, 3, none, 65, "Stable"},
+		{"production without releases", github.Repo{CreatedAt: old}, 500, 10, none, 80, "Production-Ready"},
+		{"tags without a changelog", github.Repo{CreatedAt: old}, 20, 2, tagged, 60, "Stable"},
+		{"everything", github.Repo{CreatedAt: old}, 500, 10, released, 100, "Production-Ready"},
+		{"releases stopped", github.Repo{CreatedAt: old}, 500, 10, stale, 95, "Production-Ready"},
+		{"yearly releases", github.Repo{CreatedAt: old}, 20, 2, yearly, 75, "Stable"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, level := RepoMaturityScore(&tt.repo, tt.commits, tt.contributors, tt.releases)
+			if got != tt.want || level != tt.wantLevel {
+				t.Errorf("RepoMaturityScore = %d (%s), want %d (%s)", got, level, tt.want, tt.wantLevel)
+			}
+		})
+	}
+}
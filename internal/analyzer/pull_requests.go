@@ -0,0 +1,163 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+// StaleAfter is how long an open pull request or issue may go without
+// activity before it counts as stale.
+const StaleAfter = 90 * 24 * time.Hour
+
+// PRSizeBuckets are the pull request size classes, by lines changed, in
+// increasing order. A pull request falls in the first bucket whose Max it
+// doesn't exceed.
+var PRSizeBuckets = []struct {
+	Label string
+	Max   int // lines added plus deleted; -1 for no limit
+}{
+	{"XS", 9},
+	{"S", 99},
+	{"M", 499},
+	{"L", 999},
+	{"XL", -1},
+}
+
+type PRHealth struct {
+	Total  int
+	Open   int
+	Merged int
+	// Abandoned counts pull requests closed without merging and open ones
+	// that went stale.
+	Abandoned int
+
+	MergeRate      float64 // merged share of the closed pull requests
+	AbandonedRatio float64
+
+	// Medians are zero when nothing was measured; Reviewed counts the pull
+	// requests with a review the first-review median is based on.
+	MedianTimeToFirstReview time.Duration
+	MedianTimeToMerge       time.Duration
+	Reviewed                int
+
+	// Sizes counts the pull requests with known size per PRSizeBuckets
+	// label.
+	Sizes map[string]int
+
+	Level string // Healthy, Fair, Poor, or No PRs
+}
+
+// AnalyzePullRequests computes the PR health of a repository from its pull
+// requests as of now.
+func AnalyzePullRequests(prs []github.PullRequest, now time.Time) PRHealth {
+	h := PRHealth{Total: len(prs), Sizes: make(map[string]int)}
+
+	var toMerge, toReview []time.Duration
+	closed := 0
+	for i := range prs {
+		pr := &prs[i]
+		switch {
+		case pr.Merged():
+			h.Merged++
+			closed++
+			toMerge = append(toMerge, pr.MergedAt.Sub(pr.CreatedAt))
+		case pr.State == "closed":
+			h.Abandoned++
+			closed++
+		default:
+			h.Open++
+			if now.Sub(pr.UpdatedAt) > StaleAfter {
+				h.Abandoned++
+			}
+		}
+
+		if !pr.HasDetails {
+			continue
+		}
+		if at, ok := pr.FirstReviewAt(); ok {
+			toReview = append(toReview, at.Sub(pr.CreatedAt))
+		}
+		h.Sizes[prSize(pr.Additions+pr.Deletions)]++
+	}
+
+	if closed > 0 {
+		h.MergeRate = float64(h.Merged) / float64(closed)
+	}
+	if h.Total > 0 {
+		h.AbandonedRatio = float64(h.Abandoned) / float64(h.Total)
+	}
+	h.MedianTimeToMerge = median(toMerge)
+	h.MedianTimeToFirstReview = median(toReview)
+	h.Reviewed = len(toReview)
+	h.Level = prHealthLevel(h)
+	return h
+}
+
+func prSize(lines int) string {
+	for _, b := range PRSizeBuckets {
+		if b.Max < 0 || lines <= b.Max {
+			return b.Label
+		}
+	}
+	return PRSizeBuckets[len(PRSizeBuckets)-1].Label
+}
+
+// prHealthLevel grades h by how many of these hold: most closed pull
+// requests get merged, few are abandoned, merging takes at most a week and
+// the first review comes within two days.
+func prHealthLevel(h PRHealth) string {
+	if h.Total == 0 {
+		return "No PRs"
+	}
+
+	points := 0
+	if h.MergeRate >= 0.7 {
+		points++
+	}
+	if h.AbandonedRatio <= 0.2 {
+		points++
+	}
+	if h.Merged > 0 && h.MedianTimeToMerge <= 7*24*time.Hour {
+		points++
+	}
+	if h.Reviewed > 0 && h.MedianTimeToFirstReview <= 2*24*time.Hour {
+		points++
+	}
+
+	switch {
+	case points >= 3:
+		return "Healthy"
+	case points == 2:
+		return "Fair"
+	}
+	return "Poor"
+}
+
+// median returns the median of ds, or 0 if there are none.
+func median(ds []time.Duration) time.Duration {
+	if len(ds) == 0 {
+		return 0
+	}
+	sorted := append([]time.Duration(nil), ds...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+// HumanDuration renders a median or age coarsely: minutes below an hour,
+// hours below two days, days beyond.
+func HumanDuration(d time.Duration) string {
+	switch {
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	}
+	return fmt.Sprintf("%.1fd", d.Hours()/24)
+}
@@ -0,0 +1,111 @@
+package analyzer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+func TestAnalyzePullRequests(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	at := func(d time.Duration) *time.Time {
+		t := now.Add(-30 * day).Add(d)
+		return &t
+	}
+	pr := func(state string, merged, closed *time.Time, lines int, reviewAfter time.Duration) github.PullRequest {
+		p := github.PullRequest{
+			State:     state,
+			User:      &github.User{Login: "author"},
+			CreatedAt: *at(0),
+			UpdatedAt: *at(0),
+			MergedAt:  merged,
+			ClosedAt:  closed,
+		}
+		if lines >= 0 {
+			p.HasDetails = true
+			p.Additions = lines
+		}
+		if reviewAfter > 0 {
+			p.Reviews = []github.Review{
+				{User: &github.User{Login: "author"}, State: "COMMENTED", SubmittedAt: *at(reviewAfter / 2)},
+				{User: &github.User{Login: "reviewer"}, State: "APPROVED", SubmittedAt: *at(reviewAfter)},
+			}
+		}
+		return p
+	}
+
+	tests := []struct {
+		name          string
+		prs           []github.PullRequest
+		wantMergeRate float64
+		wantAbandoned int
+		wantMerge     time.Duration
+		wantReview    time.Duration
+		wantSizes     map[string]int
+		wantLevel     string
+	}{
+		{
+			name:      "no pull requests",
+			wantSizes: map[string]int{},
+			wantLevel: "No PRs",
+		},
+		{
+			name: "fast reviews and merges",
+			prs: []github.PullRequest{
+				pr("closed", at(day), at(day), 5, time.Hour),
+				pr("closed", at(2*day), at(2*day), 50, 3*time.Hour),
+				pr("closed", at(3*day), at(3*day), 700, 5*time.Hour),
+				pr("open", nil, nil, -1, 0),
+			},
+			wantMergeRate: 1,
+			wantMerge:     2 * day,
+			wantReview:    3 * time.Hour,
+			wantSizes:     map[string]int{"XS": 1, "S": 1, "L": 1},
+			wantLevel:     "Healthy",
+		},
+		{
+			name: "abandoned and stale",
+			prs: []github.PullRequest{
+				pr("closed", at(20*day), at(20*day), 2000, 0),
+				pr("closed", nil, at(day), 300, 0),
+				pr("closed", nil, at(day), -1, 0),
+				func() github.PullRequest {
+					p := pr("open", nil, nil, -1, 0)
+					p.UpdatedAt = now.Add(-100 * day)
+					return p
+				}(),
+			},
+			wantMergeRate: 1.0 / 3,
+			wantAbandoned: 3,
+			wantMerge:     20 * day,
+			wantSizes:     map[string]int{"XL": 1, "M": 1},
+			wantLevel:     "Poor",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := AnalyzePullRequests(tt.prs, now)
+
+			if h.MergeRate != tt.wantMergeRate || h.Abandoned != tt.wantAbandoned {
+				t.Errorf("merge rate %.2f, abandoned %d; want %.2f, %d", h.MergeRate, h.Abandoned, tt.wantMergeRate, tt.wantAbandoned)
+			}
+			if h.MedianTimeToMerge != tt.wantMerge || h.MedianTimeToFirstReview != tt.wantReview {
+				t.Errorf("median merge %v, review %v; want %v, %v", h.MedianTimeToMerge, h.MedianTimeToFirstReview, tt.wantMerge, tt.wantReview)
+			}
+			if len(h.Sizes) != len(tt.wantSizes) {
+				t.Errorf("sizes = %v, want %v", h.Sizes, tt.wantSizes)
+			}
+			for label, n := range tt.wantSizes {
+				if h.Sizes[label] != n {
+					t.Errorf("sizes = %v, want %v", h.Sizes, tt.wantSizes)
+				}
+			}
+			if h.Level != tt.wantLevel {
+				t.Errorf("level = %q, want %q", h.Level, tt.wantLevel)
+			}
+		})
+	}
+}
@@ -6,19 +6,24 @@ type RecruiterSummary struct {
 	Forks           int
 	CommitsLastYear int
 	Contributors    int
-
-	MaturityScore   int
-	MaturityLevel   string
-
-	BusFactor       int
-	BusRisk         string
-
-	IssueHealth     string
-	PRHealth        string
-	ActivityLevel   string
+	// Bots counts the bots left out of Contributors.
+	Bots int
+
+	MaturityScore int
+	MaturityLevel string
+
+	BusFactor int
+	BusRisk   string
+	// BusMethod is how BusFactor was worked out: BusFromFiles or
+	// BusFromCommits.
+	BusMethod string
+
+	CommunityStandards string
+	IssueHealth        string
+	PRHealth           string
+	ActivityLevel      string
 }
 
-
 func BuildRecruiterSummary(
 	repoName string,
 	stars, forks int,
@@ -0,0 +1,162 @@
+package analyzer
+
+import (
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+// semverTag matches version tags such as v1.2.3, 1.2.3-rc.1 or
+// v2.0.0+build.5.
+var semverTag = regexp.MustCompile(`^v?(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)(-[0-9A-Za-z.-]+)?(\+[0-9A-Za-z.-]+)?$`)
+
+// changelogNames are the root files, without extension, that count as a
+// changelog.
+var changelogNames = []string{"changelog", "changes", "history", "news", "releases"}
+
+type ReleaseHealth struct {
+	// Releases counts the published releases; drafts are left out.
+	Releases    int
+	Prereleases int
+	Tags        int
+
+	PrereleaseRatio float64
+	// SemverRatio is the share of release tags, or of git tags when there
+	// are no releases, that are semantic versions.
+	SemverRatio float64
+
+	// LastRelease is zero when there are no releases. Cadence is the median
+	// time between consecutive releases, zero with fewer than two.
+	LastRelease      time.Time
+	SinceLastRelease time.Duration
+	Cadence          time.Duration
+
+	// WithNotes counts the releases with release notes; Changelog is set
+	// when the file tree has a changelog file at its root.
+	WithNotes int
+	Changelog bool
+
+	Level string // Active, Slowing, Inactive, Tags only or No releases
+}
+
+// HasReleases reports whether the repository versions its code at all,
+// with releases or at least tags.
+func (h ReleaseHealth) HasReleases() bool {
+	return h.Releases > 0 || h.Tags > 0
+}
+
+// Current reports whether the last release is recent for the repository:
+// within the six months releaseLevel calls Active, or within twice its
+// cadence for projects that release less often. Tags carry no dates, so
+// repositories with tags only are never current.
+func (h ReleaseHealth) Current() bool {
+	if h.Releases == 0 {
+		return false
+	}
+	return h.SinceLastRelease <= max(activeRelease, 2*h.Cadence)
+}
+
+// HasChangelog reports whether changes are documented, in a changelog file
+// or in release notes.
+func (h ReleaseHealth) HasChangelog() bool {
+	return h.Changelog || h.WithNotes > 0
+}
+
+// AnalyzeReleases computes the release health of a repository as of now
+// from its releases, tags and, when known, the file tree of its default
+// branch.
+func AnalyzeReleases(releases []github.Release, tags []github.Tag, tree []github.TreeEntry, now time.Time) ReleaseHealth {
+	h := ReleaseHealth{Tags: len(tags), Changelog: hasChangelog(tree)}
+
+	var dates []time.Time
+	semver := 0
+	for _, r := range releases {
+		if r.Draft {
+			continue
+		}
+		h.Releases++
+		if r.Prerelease {
+			h.Prereleases++
+		}
+		if semverTag.MatchString(r.TagName) {
+			semver++
+		}
+		if strings.TrimSpace(r.Body) != "" {
+			h.WithNotes++
+		}
+		at := r.PublishedAt
+		if at.IsZero() {
+			at = r.CreatedAt
+		}
+		dates = append(dates, at)
+		if at.After(h.LastRelease) {
+			h.LastRelease = at
+		}
+	}
+
+	switch {
+	case h.Releases > 0:
+		h.PrereleaseRatio = float64(h.Prereleases) / float64(h.Releases)
+		h.SemverRatio = float64(semver) / float64(h.Releases)
+		h.SinceLastRelease = now.Sub(h.LastRelease)
+		h.Cadence = cadence(dates)
+	case h.Tags > 0:
+		for _, t := range tags {
+			if semverTag.MatchString(t.Name) {
+				semver++
+			}
+		}
+		h.SemverRatio = float64(semver) / float64(h.Tags)
+	}
+	h.Level = releaseLevel(h)
+	return h
+}
+
+// cadence returns the median gap between consecutive dates.
+func cadence(dates []time.Time) time.Duration {
+	sorted := append([]time.Time(nil), dates...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Before(sorted[j]) })
+	var gaps []time.Duration
+	for i := 1; i < len(sorted); i++ {
+		gaps = append(gaps, sorted[i].Sub(sorted[i-1]))
+	}
+	return median(gaps)
+}
+
+func hasChangelog(tree []github.TreeEntry) bool {
+	for _, e := range tree {
+		if e.Type != "blob" || strings.Contains(e.Path, "/") {
+			continue
+		}
+		name := strings.ToLower(strings.TrimSuffix(e.Path, path.Ext(e.Path)))
+		for _, c := range changelogNames {
+			if name == c {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// activeRelease is how recent the last release of an Active repository is.
+const activeRelease = 182 * 24 * time.Hour
+
+// releaseLevel grades how recently h last released: within six months is
+// Active, within a year Slowing, anything older Inactive.
+func releaseLevel(h ReleaseHealth) string {
+	switch {
+	case h.Releases == 0 && h.Tags > 0:
+		return "Tags only"
+	case h.Releases == 0:
+		return "No releases"
+	case h.SinceLastRelease <= activeRelease:
+		return "Active"
+	case h.SinceLastRelease <= 365*24*time.Hour:
+		return "Slowing"
+	}
+	return "Inactive"
+}
@@ -0,0 +1,88 @@
+package analyzer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+func TestAnalyzeReleases(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	release := func(tag string, ago time.Duration, notes string) github.Release {
+		return github.Release{TagName: tag, Body: notes, PublishedAt: now.Add(-ago)}
+	}
+	prerelease := func(r github.Release) github.Release {
+		r.Prerelease = true
+		return r
+	}
+
+	tests := []struct {
+		name          string
+		releases      []github.Release
+		tags          []github.Tag
+		tree          []github.TreeEntry
+		wantReleases  int
+		wantSemver    float64
+		wantPre       float64
+		wantCadence   time.Duration
+		wantChangelog bool
+		wantLevel     string
+	}{
+		{
+			name:      "nothing",
+			wantLevel: "No releases",
+		},
+		{
+			name:       "tags only",
+			tags:       []github.Tag{{Name: "v1.0.0"}, {Name: "v0.9.0"}, {Name: "nightly"}, {Name: "2.0"}},
+			wantSemver: 0.5,
+			wantLevel:  "Tags only",
+		},
+		{
+			name: "regular semver releases",
+			releases: []github.Release{
+				{TagName: "v2.0.0", Draft: true},
+				release("v1.2.0", 10*day, "notes"),
+				prerelease(release("v1.2.0-rc.1", 20*day, "")),
+				release("v1.1.0", 40*day, ""),
+				release("1.0.0+build.7", 100*day, ""),
+			},
+			wantReleases:  4,
+			wantSemver:    1,
+			wantPre:       0.25,
+			wantCadence:   20 * day,
+			wantChangelog: true,
+			wantLevel:     "Active",
+		},
+		{
+			name:          "abandoned, with a changelog file",
+			releases:      []github.Release{release("release-3", 400*day, "")},
+			tree:          []github.TreeEntry{{Path: "docs/CHANGELOG.md", Type: "blob"}, {Path: "CHANGES.rst", Type: "blob"}},
+			wantReleases:  1,
+			wantChangelog: true,
+			wantLevel:     "Inactive",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := AnalyzeReleases(tt.releases, tt.tags, tt.tree, now)
+
+			if h.Releases != tt.wantReleases || h.SemverRatio != tt.wantSemver || h.PrereleaseRatio != tt.wantPre {
+				t.Errorf("releases %d, semver %.2f, prerelease %.2f; want %d, %.2f, %.2f",
+					h.Releases, h.SemverRatio, h.PrereleaseRatio, tt.wantReleases, tt.wantSemver, tt.wantPre)
+			}
+			if h.Cadence != tt.wantCadence {
+				t.Errorf("cadence = %v, want %v", h.Cadence, tt.wantCadence)
+			}
+			if h.HasChangelog() != tt.wantChangelog {
+				t.Errorf("HasChangelog() = %v, want %v", h.HasChangelog(), tt.wantChangelog)
+			}
+			if h.Level != tt.wantLevel {
+				t.Errorf("level = %q, want %q", h.Level, tt.wantLevel)
+			}
+		})
+	}
+}
@@ -0,0 +1,200 @@
+package analyzer
+
+import (
+	"sort"
+	"time"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+// Retention windows, in days.
+const (
+	// retentionDays is how long after their first commit a newcomer has to
+	// commit again to count as retained.
+	retentionDays = 90
+	// quietDays is how long a core member can go without committing,
+	// while others committed in that time, before they count as gone
+	// quiet.
+	quietDays = 90
+)
+
+// MonthlyNewcomers counts the people whose first commit was in Month,
+// formatted as 2006-01.
+type MonthlyNewcomers struct {
+	Month string
+	New   int
+}
+
+// CoreMember is one of the people behind most of the commits.
+type CoreMember struct {
+	Author     string
+	Commits    int
+	LastCommit time.Time
+	// DaysSince is the number of days from LastCommit to the analysis.
+	// Quiet is set when it is 90 or more while others committed in the
+	// last 90 days.
+	DaysSince int
+	Quiet     bool
+}
+
+// RetentionReport is whether newcomers stick around and whether the core
+// team is still there, worked out from a commit history.
+type RetentionReport struct {
+	// Contributors counts the people with commits in the history and
+	// Commits their commits, from Since on.
+	Contributors int
+	Commits      int
+	Since        time.Time
+	// NewcomersSince is when the newcomers counted below start: Since
+	// when the history goes back to the first commits, or the first month
+	// starting a retention window after it when it was cut short, since
+	// anyone who committed before the cut looks new until then. Truncated
+	// says which, and Newcomers counts the people whose first commit is
+	// from NewcomersSince on.
+	NewcomersSince time.Time
+	Truncated      bool
+	Newcomers      int
+	// NewPerMonth counts newcomers for every month from NewcomersSince to
+	// the analysis, oldest first.
+	NewPerMonth []MonthlyNewcomers
+	// Eligible counts the newcomers whose first commit is at least 90 days
+	// old, and Retained those of them who committed again 90 days or more
+	// after it. Retention is their ratio, 0 when nobody is eligible.
+	Eligible  int
+	Retained  int
+	Retention float64
+	// OneTime counts the newcomers with a single commit and OneTimeRatio
+	// their share of Newcomers.
+	OneTime      int
+	OneTimeRatio float64
+	// Core are the fewest people behind 80% of the commits, most commits
+	// first.
+	Core []CoreMember
+}
+
+// GoneQuiet returns the core members who stopped committing while the
+// project went on.
+func (r RetentionReport) GoneQuiet() []CoreMember {
+	var quiet []CoreMember
+	for _, m := range r.Core {
+		if m.Quiet {
+			quiet = append(quiet, m)
+		}
+	}
+	return quiet
+}
+
+// contributorHistory is when one person committed first and last, and how
+// often.
+type contributorHistory struct {
+	first, last time.Time
+	commits     int
+}
+
+// AnalyzeRetention works out newcomer retention and the core team from
+// commits, as of now. truncated says the history was cut short of the first
+// commits. roster names the authors and leaves out bots.
+func AnalyzeRetention(commits []github.Commit, truncated bool, roster *Roster, now time.Time) RetentionReport {
+	var r RetentionReport
+	people := make(map[string]*contributorHistory)
+	var latest time.Time
+	for i := range commits {
+		c := &commits[i]
+		date := c.Commit.Author.Date
+		if date.IsZero() || roster.Skip(c) {
+			continue
+		}
+		author := roster.Author(c)
+		h := people[author]
+		if h == nil {
+			h = &contributorHistory{first: date, last: date}
+			people[author] = h
+		}
+		if date.Before(h.first) {
+			h.first = date
+		}
+		if date.After(h.last) {
+			h.last = date
+		}
+		h.commits++
+		r.Commits++
+		if r.Since.IsZero() || date.Before(r.Since) {
+			r.Since = date
+		}
+		if date.After(latest) {
+			latest = date
+		}
+	}
+	r.Contributors = len(people)
+	if r.Contributors == 0 {
+		return r
+	}
+
+	r.NewcomersSince, r.Truncated = r.Since, truncated
+	if truncated {
+		since := r.Since.UTC().AddDate(0, 0, retentionDays)
+		r.NewcomersSince = time.Date(since.Year(), since.Month(), 1, 0, 0, 0, 0, time.UTC)
+		if r.NewcomersSince.Before(since) {
+			r.NewcomersSince = r.NewcomersSince.AddDate(0, 1, 0)
+		}
+	}
+	newcomers := make(map[string]int)
+	eligibleBefore := now.AddDate(0, 0, -retentionDays)
+	for _, h := range people {
+		if h.first.Before(r.NewcomersSince) {
+			continue
+		}
+		r.Newcomers++
+		newcomers[h.first.UTC().Format("2006-01")]++
+		if !h.first.After(eligibleBefore) {
+			r.Eligible++
+			if !h.last.Before(h.first.AddDate(0, 0, retentionDays)) {
+				r.Retained++
+			}
+		}
+		if h.commits == 1 {
+			r.OneTime++
+		}
+	}
+	first := time.Date(r.NewcomersSince.UTC().Year(), r.NewcomersSince.UTC().Month(), 1, 0, 0, 0, 0, time.UTC)
+	for m := first; !m.After(now); m = m.AddDate(0, 1, 0) {
+		month := m.Format("2006-01")
+		r.NewPerMonth = append(r.NewPerMonth, MonthlyNewcomers{Month: month, New: newcomers[month]})
+	}
+	if r.Eligible > 0 {
+		r.Retention = float64(r.Retained) / float64(r.Eligible)
+	}
+	if r.Newcomers > 0 {
+		r.OneTimeRatio = float64(r.OneTime) / float64(r.Newcomers)
+	}
+
+	authors := make([]string, 0, len(people))
+	for author := range people {
+		authors = append(authors, author)
+	}
+	sort.Slice(authors, func(i, j int) bool {
+		a, b := people[authors[i]], people[authors[j]]
+		if a.commits != b.commits {
+			return a.commits > b.commits
+		}
+		return authors[i] < authors[j]
+	})
+	active := latest.After(now.AddDate(0, 0, -quietDays))
+	covered := 0
+	for _, author := range authors {
+		if covered*5 >= r.Commits*4 {
+			break
+		}
+		h := people[author]
+		covered += h.commits
+		days := int(now.Sub(h.last).Hours() / 24)
+		r.Core = append(r.Core, CoreMember{
+			Author:     author,
+			Commits:    h.commits,
+			LastCommit: h.last,
+			DaysSince:  days,
+			Quiet:      days >= quietDays && active,
+		})
+	}
+	return r
+}
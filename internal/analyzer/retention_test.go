@@ -0,0 +1,125 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+// datedCommits are commits by author on each of dates, given as 2006-01-02.
+func datedCommits(author string, dates ...string) []github.Commit {
+	var commits []github.Commit
+	for _, d := range dates {
+		date, err := time.Parse("2006-01-02", d)
+		if err != nil {
+			panic(err)
+		}
+		c := github.Commit{Author: &github.User{Login: author}}
+		c.Commit.Author.Date = date
+		commits = append(commits, c)
+	}
+	return commits
+}
+
+func TestAnalyzeRetention(t *testing.T) {
+	now := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	history := func(groups ...[]github.Commit) []github.Commit {
+		var commits []github.Commit
+		for _, g := range groups {
+			commits = append(commits, g...)
+		}
+		return commits
+	}
+
+	tests := []struct {
+		name         string
+		commits      []github.Commit
+		truncated    bool
+		contributors int
+		eligible     int
+		retained     int
+		oneTime      int
+		core         []string
+		newPerMonth  string
+	}{
+		{
+			name: "no commits",
+		},
+		{
+			name: "newcomers and a quiet core member",
+			commits: history(
+				datedCommits("alice", "2026-01-05", "2026-02-01", "2026-03-10", "2026-05-01", "2026-08-01", "2026-09-20"),
+				datedCommits("bob", "2026-02-01", "2026-02-10", "2026-02-15"),
+				datedCommits("carol", "2026-09-01"),
+				datedCommits("dependabot[bot]", "2025-12-01", "2026-09-30"),
+			),
+			contributors: 3,
+			eligible:     2,
+			retained:     1,
+			oneTime:      1,
+			core:         []string{"alice 6 11d", "bob 3 228d quiet"},
+			newPerMonth:  "2026-01:1 2026-02:1 2026-03:0 2026-04:0 2026-05:0 2026-06:0 2026-07:0 2026-08:0 2026-09:1 2026-10:0",
+		},
+		{
+			name: "whole project quiet",
+			commits: history(
+				datedCommits("alice", "2026-05-01", "2026-05-02", "2026-06-01"),
+				datedCommits("bob", "2026-05-03"),
+			),
+			contributors: 2,
+			eligible:     2,
+			oneTime:      1,
+			core:         []string{"alice 3 122d", "bob 1 151d"},
+			newPerMonth:  "2026-05:2 2026-06:0 2026-07:0 2026-08:0 2026-09:0 2026-10:0",
+		},
+		{
+			// Only people first seen from April on, a retention window
+			// after the cut, count as newcomers.
+			name: "truncated history",
+			commits: history(
+				datedCommits("alice", "2025-12-03", "2026-02-01", "2026-06-01", "2026-09-25"),
+				datedCommits("bob", "2026-01-10"),
+				datedCommits("carol", "2026-04-02", "2026-08-01"),
+				datedCommits("dave", "2026-05-05"),
+			),
+			truncated:    true,
+			contributors: 4,
+			eligible:     2,
+			retained:     1,
+			oneTime:      1,
+			core:         []string{"alice 4 6d", "carol 2 61d", "bob 1 264d quiet"},
+			newPerMonth:  "2026-04:1 2026-05:1 2026-06:0 2026-07:0 2026-08:0 2026-09:0 2026-10:0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := AnalyzeRetention(tt.commits, tt.truncated, NewIdentities().Roster(tt.commits), now)
+			if r.Contributors != tt.contributors || r.Eligible != tt.eligible || r.Retained != tt.retained || r.OneTime != tt.oneTime {
+				t.Errorf("%d contributors, %d of %d retained, %d one-time; want %d, %d of %d, %d",
+					r.Contributors, r.Retained, r.Eligible, r.OneTime, tt.contributors, tt.retained, tt.eligible, tt.oneTime)
+			}
+			var core []string
+			for _, m := range r.Core {
+				s := fmt.Sprintf("%s %d %dd", m.Author, m.Commits, m.DaysSince)
+				if m.Quiet {
+					s += " quiet"
+				}
+				core = append(core, s)
+			}
+			if strings.Join(core, ", ") != strings.Join(tt.core, ", ") {
+				t.Errorf("core = %v, want %v", core, tt.core)
+			}
+			var months []string
+			for _, m := range r.NewPerMonth {
+				months = append(months, fmt.Sprintf("%s:%d", m.Month, m.New))
+			}
+			if got := strings.Join(months, " "); got != tt.newPerMonth {
+				t.Errorf("new per month = %s, want %s", got, tt.newPerMonth)
+			}
+		})
+	}
+}
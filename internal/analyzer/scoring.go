@@ -0,0 +1,149 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// Signal is one named input of the health score. Measure returns the raw
+// value the signal looks at, that value normalized to 0..1, and a short
+// explanation of both for people reading the score. Signals whose Applies
+// returns false for an input, because what they look at wasn't measured,
+// are left out of its score.
+type Signal struct {
+	Name    string
+	Measure func(in HealthInput) (raw, normalized float64, explanation string)
+	Applies func(in HealthInput) bool
+}
+
+// SignalScore is how one signal contributed to a health score: Points is
+// Normalized times Weight. Weight is the profile's weight, rescaled when
+// other signals didn't apply; see ScoreHealth.
+type SignalScore struct {
+	Name        string
+	Raw         float64
+	Normalized  float64
+	Weight      float64
+	Points      float64
+	Explanation string
+}
+
+// HealthScore is a health score with the breakdown it was computed from.
+type HealthScore struct {
+	Score   int
+	Profile string
+	Base    float64
+	Signals []SignalScore
+}
+
+// Profile weighs the health signals. Signals without a weight don't count;
+// the score is Base plus the weighted signals, clamped to 0..100. Negative
+// weights are deductions.
+type Profile struct {
+	Name    string             `json:"name" yaml:"name"`
+	Base    float64            `json:"base" yaml:"base"`
+	Weights map[string]float64 `json:"weights" yaml:"weights"`
+}
+
+// LoadProfile reads a profile from a JSON file, or from a YAML file for any
+// other extension. A profile without a name is named after its file.
+func LoadProfile(path string) (Profile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Profile{}, fmt.Errorf("reading health profile: %w", err)
+	}
+
+	var p Profile
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		err = json.Unmarshal(data, &p)
+	} else {
+		err = yaml.Unmarshal(data, &p)
+	}
+	if err != nil {
+		return Profile{}, fmt.Errorf("parsing health profile %s: %w", path, err)
+	}
+	if err := p.validate(); err != nil {
+		return Profile{}, fmt.Errorf("health profile %s: %w", path, err)
+	}
+	if p.Name == "" {
+		p.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	return p, nil
+}
+
+// validate rejects weights for signals that don't exist, which are most
+// likely typos.
+func (p Profile) validate() error {
+	known := make(map[string]bool)
+	var names []string
+	for _, s := range HealthSignals {
+		known[s.Name] = true
+		names = append(names, s.Name)
+	}
+	var unknown []string
+	for name := range p.Weights {
+		if !known[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("unknown signals %s (want %s)", strings.Join(unknown, ", "), strings.Join(names, ", "))
+	}
+	return nil
+}
+
+// ScoreHealth scores in with the HealthSignals weighed by p. When a signal
+// doesn't apply to in, the positive weights of those that do are scaled up
+// to make up for it, so a repository isn't marked down for what wasn't
+// measured. Deductions are never scaled.
+func ScoreHealth(in HealthInput, p Profile) HealthScore {
+	h := HealthScore{Profile: p.Name, Base: p.Base}
+	applies := make(map[string]bool)
+	var weighted, applied float64
+	for _, s := range HealthSignals {
+		weight := p.Weights[s.Name]
+		applies[s.Name] = s.Applies == nil || s.Applies(in)
+		if weight > 0 {
+			weighted += weight
+			if applies[s.Name] {
+				applied += weight
+			}
+		}
+	}
+	scale := 1.0
+	if applied > 0 {
+		scale = weighted / applied
+	}
+
+	total := p.Base
+	for _, s := range HealthSignals {
+		weight, ok := p.Weights[s.Name]
+		if !ok || !applies[s.Name] {
+			continue
+		}
+		if weight > 0 {
+			weight *= scale
+		}
+		raw, normalized, explanation := s.Measure(in)
+		score := SignalScore{
+			Name:        s.Name,
+			Raw:         raw,
+			Normalized:  normalized,
+			Weight:      weight,
+			Points:      normalized * weight,
+			Explanation: explanation,
+		}
+		total += score.Points
+		h.Signals = append(h.Signals, score)
+	}
+	h.Score = int(math.Round(math.Max(0, math.Min(100, total))))
+	return h
+}
@@ -0,0 +1,127 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+func TestScoreHealth(t *testing.T) {
+	in := HealthInput{
+		Repo:    &github.Repo{Description: "x", Stars: 10},
+		Commits: make([]github.Commit, 30),
+		Issues:  IssueHealth{Total: 8, Open: 4, Level: "Fair"},
+	}
+
+	some := &CommunityStandards{Found: 3, Completeness: 33}
+	all := &CommunityStandards{Found: len(CommunityChecks), Completeness: 100}
+	scanned := func(severities ...Severity) *VulnerabilityReport {
+		r := &VulnerabilityReport{Checked: 12}
+		for _, s := range severities {
+			r.Vulnerabilities = append(r.Vulnerabilities, Vulnerability{Severity: s})
+		}
+		return r
+	}
+	withVulns := []string{"description", "stars", "commits", "issues", "community", "vulnerabilities"}
+
+	tests := []struct {
+		name        string
+		profile     Profile
+		community   *CommunityStandards
+		vulns       *VulnerabilityReport
+		want        int
+		wantSignals []string
+	}{
+		{"default", DefaultProfile, some, nil, 81, []string{"description", "stars", "commits", "issues", "community"}},
+		{"default with every community file", DefaultProfile, all, nil, 88, []string{"description", "stars", "commits", "issues", "community"}},
+		{"default without community files", DefaultProfile, nil, nil, 85, []string{"description", "stars", "commits", "issues"}},
+		{"default with a clean scan", DefaultProfile, some, scanned(), 81, withVulns},
+		{"default with a medium advisory", DefaultProfile, some, scanned(SeverityMedium), 76, withVulns},
+		{"default with two medium advisories", DefaultProfile, some, scanned(SeverityMedium, SeverityMedium), 71, withVulns},
+		{"default with a critical advisory", DefaultProfile, some, scanned(SeverityCritical), 61, withVulns},
+		{"default with advisories past the cap", DefaultProfile, some, scanned(SeverityCritical, SeverityHigh), 61, withVulns},
+		{"subset", Profile{Base: 10, Weights: map[string]float64{"issues": 30, "commits": 25}}, some, nil, 50, []string{"commits", "issues"}},
+		{"penalty", Profile{Weights: map[string]float64{"description": -20}}, some, nil, 0, []string{"description"}},
+		{"capped", Profile{Base: 90, Weights: map[string]float64{"commits": 50}}, some, nil, 100, []string{"commits"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := in
+			in.Community, in.Vulnerabilities = tt.community, tt.vulns
+			h := ScoreHealth(in, tt.profile)
+			if h.Score != tt.want {
+				t.Errorf("score = %d, want %d (%+v)", h.Score, tt.want, h.Signals)
+			}
+			if len(h.Signals) != len(tt.wantSignals) {
+				t.Fatalf("signals = %+v, want %v", h.Signals, tt.wantSignals)
+			}
+			for i, s := range h.Signals {
+				if s.Name != tt.wantSignals[i] || s.Points != s.Normalized*s.Weight || s.Explanation == "" {
+					t.Errorf("signal %d = %+v, want %s", i, s, tt.wantSignals[i])
+				}
+			}
+		})
+	}
+
+	// Signals that don't apply leave their weight to the others, so a
+	// repository without community data can still reach 100.
+	full := HealthInput{
+		Repo:    &github.Repo{Description: "x", Stars: 100},
+		Commits: make([]github.Commit, 30),
+		Issues:  IssueHealth{Total: 3, Level: "Healthy"},
+	}
+	if h := ScoreHealth(full, DefaultProfile); h.Score != 100 {
+		t.Errorf("score without community data = %d, want 100 (%+v)", h.Score, h.Signals)
+	}
+	full.Vulnerabilities = scanned(SeverityHigh)
+	if h := ScoreHealth(full, DefaultProfile); h.Score != 90 {
+		t.Errorf("score with a high advisory = %d, want 90 (%+v)", h.Score, h.Signals)
+	}
+}
+
+func TestLoadProfile(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	tests := []struct {
+		name     string
+		path     string
+		wantName string
+		wantBase float64
+		wantErr  string
+	}{
+		{"yaml named after its file", write("strict.yml", "base: 40\nweights:\n  stars: 30\n"), "strict", 40, ""},
+		{"json", write("p.json", `{"name": "team", "weights": {"issues": 5}}`), "team", 0, ""},
+		{"malformed", write("bad.json", `{"weights": [1]}`), "", 0, "parsing health profile"},
+		{"unknown signal", write("typo.yaml", "weights:\n  stras: 1\n"), "", 0, "unknown signals stras"},
+		{"missing", filepath.Join(dir, "nope.yaml"), "", 0, "reading health profile"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := LoadProfile(tt.path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadProfile: %v", err)
+			}
+			if p.Name != tt.wantName || p.Base != tt.wantBase {
+				t.Errorf("profile = %+v", p)
+			}
+		})
+	}
+}
@@ -1 +1,392 @@
-package analyzer
\ No newline at end of file
+package analyzer
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+// Severity ranks a security finding.
+type Severity string
+
+const (
+	SeverityCritical Severity = "critical"
+	SeverityHigh     Severity = "high"
+	SeverityMedium   Severity = "medium"
+	SeverityLow      Severity = "low"
+	// SeverityUnknown is for advisories that don't rate themselves.
+	SeverityUnknown Severity = "unknown"
+)
+
+var severityRank = map[Severity]int{SeverityCritical: 0, SeverityHigh: 1, SeverityMedium: 2, SeverityLow: 3, SeverityUnknown: 4}
+
+// SecurityFinding is one problem found by a security check. Path is the
+// file it is about, if any.
+type SecurityFinding struct {
+	Check    string
+	Severity Severity
+	Path     string
+	Message  string
+}
+
+// SecurityCheckResult is the outcome of one security check. Checks that
+// could not be run, like branch protection when the branch is unknown, are
+// Skipped and don't count towards the score.
+type SecurityCheckResult struct {
+	Name    string
+	Weight  int
+	Passed  bool
+	Skipped bool
+	Detail  string
+}
+
+type SecurityReport struct {
+	// Score is the weight of the passed checks as a percentage of the
+	// weight of the checks that ran.
+	Score    int
+	Level    string
+	Checks   []SecurityCheckResult
+	Findings []SecurityFinding // most severe first
+
+	Workflows       int
+	PinnedActions   int
+	UnpinnedActions int
+}
+
+// SecurityInput is what AnalyzeSecurity looks at. Files holds the contents
+// of the files that were fetched, by path; Branch is the default branch, or
+// nil when unknown, and Protection its protection rules, or nil when they
+// could not be read. Vulnerabilities is nil when no advisory database was
+// loaded.
+type SecurityInput struct {
+	Tree            []github.TreeEntry
+	Files           map[string][]byte
+	Branch          *github.Branch
+	Protection      *github.BranchProtection
+	Vulnerabilities *VulnerabilityReport
+}
+
+// Names of the security checks, in the order they are reported.
+const (
+	CheckSecurityPolicy   = "Security policy"
+	CheckDependencyUpdate = "Dependency updates"
+	CheckCodeScanning     = "Code scanning"
+	CheckPinnedActions    = "Pinned actions"
+	CheckSecrets          = "No secrets"
+	CheckBranchProtection = "Branch protection"
+	CheckVulnerabilities  = "Vulnerabilities"
+)
+
+var (
+	// dependencyUpdateConfigs are the config files of Dependabot and
+	// Renovate.
+	dependencyUpdateConfigs = map[string]string{
+		".github/dependabot.yml":  "Dependabot",
+		".github/dependabot.yaml": "Dependabot",
+		"renovate.json":           "Renovate",
+		"renovate.json5":          "Renovate",
+		".renovaterc":             "Renovate",
+		".renovaterc.json":        "Renovate",
+		".renovaterc.json5":       "Renovate",
+		".github/renovate.json":   "Renovate",
+		".github/renovate.json5":  "Renovate",
+		".gitlab/renovate.json":   "Renovate",
+	}
+
+	// scanningActions are the actions of code and dependency scanners, by
+	// the prefix of what workflows use.
+	scanningActions = map[string]string{
+		"github/codeql-action/":                "CodeQL",
+		"ossf/scorecard-action":                "Scorecard",
+		"aquasecurity/trivy-action":            "Trivy",
+		"snyk/actions/":                        "Snyk",
+		"securego/gosec":                       "gosec",
+		"semgrep/semgrep-action":               "Semgrep",
+		"returntocorp/semgrep-action":          "Semgrep",
+		"anchore/scan-action":                  "Grype",
+		"github/dependency-review-action":      "Dependency review",
+		"golang/govulncheck-action":            "govulncheck",
+		"google/osv-scanner-action/":           "OSV-Scanner",
+		"sonarsource/sonarcloud-github-action": "SonarCloud",
+	}
+
+	usesLine = regexp.MustCompile(`(?m)^[\s-]*uses:\s*["']?([^\s"'#]+)`)
+	fullSHA  = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+	secretPatterns = []struct {
+		re       *regexp.Regexp
+		what     string
+		severity Severity
+	}{
+		{regexp.MustCompile(`-----BEGIN ((RSA|DSA|EC|OPENSSH|PGP|ENCRYPTED) )?PRIVATE KEY( BLOCK)?-----`), "private key", SeverityCritical},
+		{regexp.MustCompile(`\b(AKIA|ASIA)[0-9A-Z]{16}\b`), "AWS access key ID", SeverityCritical},
+		{regexp.MustCompile(`(?i)aws_secret_access_key\s*[:=]\s*["']?[A-Za-z0-9/+=]{40}`), "AWS secret access key", SeverityCritical},
+		{regexp.MustCompile(`\b(ghp|gho|ghs|ghu)_[A-Za-z0-9]{36}\b|\bgithub_pat_[A-Za-z0-9_]{60,}`), "GitHub token", SeverityHigh},
+	}
+
+	// privateKeyFiles are the names of private key files.
+	privateKeyFiles = map[string]bool{"id_rsa": true, "id_dsa": true, "id_ecdsa": true, "id_ed25519": true}
+	keyStoreExts    = map[string]bool{".p12": true, ".pfx": true, ".key": true, ".jks": true}
+
+	// secretScanExts are the extensions of the files, besides workflows,
+	// whose contents are scanned for secrets: configuration and key
+	// material. Scripts are scanned along with the source files whose
+	// license headers are checked.
+	secretScanExts = map[string]bool{
+		".yml": true, ".yaml": true, ".json": true, ".toml": true, ".ini": true, ".cfg": true, ".conf": true,
+		".properties": true, ".env": true, ".tf": true, ".tfvars": true, ".pem": true,
+	}
+)
+
+// maxScannedFileSize is the size of the largest file whose contents are
+// scanned for secrets.
+const maxScannedFileSize = 100 << 10
+
+// isWorkflow reports whether p is a GitHub Actions workflow.
+func isWorkflow(p string) bool {
+	ext := path.Ext(p)
+	return path.Dir(p) == ".github/workflows" && (ext == ".yml" || ext == ".yaml")
+}
+
+// isEnvFile reports whether p is a dotenv file other than an example one.
+func isEnvFile(p string) bool {
+	base := strings.ToLower(path.Base(p))
+	if base != ".env" && !strings.HasPrefix(base, ".env.") {
+		return false
+	}
+	for _, suffix := range []string{".example", ".sample", ".template", ".dist", ".defaults"} {
+		if strings.HasSuffix(base, suffix) {
+			return false
+		}
+	}
+	return true
+}
+
+// SecurityScanCandidates picks the files of tree whose contents the
+// security checks need: every workflow, then up to n more files that could
+// hold secrets, in path order.
+func SecurityScanCandidates(tree []github.TreeEntry, n int) []string {
+	var workflows, others []string
+	for _, e := range tree {
+		if e.Type != "blob" || e.Size > maxScannedFileSize {
+			continue
+		}
+		switch {
+		case isWorkflow(e.Path):
+			workflows = append(workflows, e.Path)
+		case isEnvFile(e.Path) || secretScanExts[strings.ToLower(path.Ext(e.Path))]:
+			others = append(others, e.Path)
+		}
+	}
+	sort.Strings(workflows)
+	sort.Strings(others)
+	if len(others) > n {
+		others = others[:n]
+	}
+	return append(workflows, others...)
+}
+
+// AnalyzeSecurity checks a repository for the basics of a secure setup and
+// scores it on them.
+func AnalyzeSecurity(in SecurityInput) SecurityReport {
+	var r SecurityReport
+	find := func(check string, sev Severity, p, format string, args ...interface{}) {
+		r.Findings = append(r.Findings, SecurityFinding{Check: check, Severity: sev, Path: p, Message: fmt.Sprintf(format, args...)})
+	}
+
+	policy := CommunityCheck{Item: CheckSecurityPolicy, Names: []string{"security"}}
+	if p, ok := policy.find(in.Tree); ok {
+		r.check(CheckSecurityPolicy, 15, true, p)
+	} else {
+		r.check(CheckSecurityPolicy, 15, false, "no SECURITY.md")
+		find(CheckSecurityPolicy, SeverityLow, "", "no SECURITY.md telling reporters how to disclose vulnerabilities")
+	}
+
+	var updaters, scanners []string
+	for _, e := range in.Tree {
+		if tool, ok := dependencyUpdateConfigs[e.Path]; ok && e.Type == "blob" {
+			updaters = appendUnique(updaters, tool)
+		}
+	}
+	if len(updaters) > 0 {
+		r.check(CheckDependencyUpdate, 20, true, strings.Join(updaters, ", "))
+	} else {
+		r.check(CheckDependencyUpdate, 20, false, "no Dependabot or Renovate config")
+		find(CheckDependencyUpdate, SeverityMedium, "", "dependencies are not updated automatically")
+	}
+
+	for _, e := range in.Tree {
+		if e.Type != "blob" || !isWorkflow(e.Path) {
+			continue
+		}
+		r.Workflows++
+		for _, m := range usesLine.FindAllSubmatch(in.Files[e.Path], -1) {
+			uses := string(m[1])
+			lower := strings.ToLower(uses)
+			for prefix, tool := range scanningActions {
+				if strings.HasPrefix(lower, prefix) {
+					scanners = appendUnique(scanners, tool)
+				}
+			}
+			if strings.HasPrefix(uses, "./") || strings.HasPrefix(uses, "docker://") && strings.Contains(uses, "@sha256:") {
+				continue
+			}
+			at := strings.LastIndex(uses, "@")
+			if at >= 0 && fullSHA.MatchString(uses[at+1:]) {
+				r.PinnedActions++
+				continue
+			}
+			r.UnpinnedActions++
+			find(CheckPinnedActions, SeverityMedium, e.Path, "%s is not pinned to a commit SHA", uses)
+		}
+	}
+	sort.Strings(scanners)
+	if len(scanners) > 0 {
+		r.check(CheckCodeScanning, 20, true, strings.Join(scanners, ", "))
+	} else {
+		r.check(CheckCodeScanning, 20, false, "no scanning workflow")
+		find(CheckCodeScanning, SeverityMedium, "", "no workflow runs CodeQL or another scanner")
+	}
+	switch {
+	case r.UnpinnedActions > 0:
+		r.check(CheckPinnedActions, 15, false, fmt.Sprintf("%d of %d not pinned", r.UnpinnedActions, r.PinnedActions+r.UnpinnedActions))
+	case r.PinnedActions > 0:
+		r.check(CheckPinnedActions, 15, true, fmt.Sprintf("all %d pinned", r.PinnedActions))
+	default:
+		r.check(CheckPinnedActions, 15, true, "no actions used")
+	}
+
+	secrets := 0
+	for _, e := range in.Tree {
+		if e.Type != "blob" {
+			continue
+		}
+		base := strings.ToLower(path.Base(e.Path))
+		switch {
+		case isEnvFile(e.Path):
+			secrets++
+			find(CheckSecrets, SeverityHigh, e.Path, "environment file committed")
+		case privateKeyFiles[base] || keyStoreExts[path.Ext(base)]:
+			secrets++
+			find(CheckSecrets, SeverityHigh, e.Path, "key file committed")
+		}
+	}
+	paths := make([]string, 0, len(in.Files))
+	for p := range in.Files {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		for _, s := range secretPatterns {
+			if s.re.Match(in.Files[p]) {
+				secrets++
+				find(CheckSecrets, s.severity, p, "%s committed", s.what)
+			}
+		}
+	}
+	if secrets > 0 {
+		r.check(CheckSecrets, 20, false, fmt.Sprintf("%d found in %d scanned files", secrets, len(in.Files)))
+	} else {
+		r.check(CheckSecrets, 20, true, fmt.Sprintf("none in %d scanned files", len(in.Files)))
+	}
+
+	switch b := in.Branch; {
+	case b == nil:
+		r.Checks = append(r.Checks, SecurityCheckResult{Name: CheckBranchProtection, Weight: 10, Skipped: true, Detail: "default branch unknown"})
+	case !b.Protected:
+		r.check(CheckBranchProtection, 10, false, b.Name+" is not protected")
+		find(CheckBranchProtection, SeverityHigh, "", "%s can be pushed to and force-pushed by anyone with write access", b.Name)
+	case in.Protection == nil:
+		r.check(CheckBranchProtection, 10, true, b.Name+" is protected (rules need admin rights to read)")
+	default:
+		r.check(CheckBranchProtection, 10, true, b.Name+" is protected: "+protectionRules(in.Protection))
+		if in.Protection.RequiredReviews == nil || in.Protection.RequiredReviews.Count == 0 {
+			find(CheckBranchProtection, SeverityLow, "", "%s needs no approving review to merge", b.Name)
+		}
+		if p := in.Protection.AllowForcePushes; p != nil && p.Enabled {
+			find(CheckBranchProtection, SeverityMedium, "", "%s allows force pushes", b.Name)
+		}
+	}
+
+	switch v := in.Vulnerabilities; {
+	case v == nil:
+		r.Checks = append(r.Checks, SecurityCheckResult{Name: CheckVulnerabilities, Weight: 20, Skipped: true, Detail: "no advisory database loaded"})
+	case len(v.Vulnerabilities) > 0:
+		r.check(CheckVulnerabilities, 20, false, fmt.Sprintf("%d found in %d of %s",
+			len(v.Vulnerabilities), v.Packages(), v.checkedText()))
+		for _, vuln := range v.Vulnerabilities {
+			find(CheckVulnerabilities, vuln.Severity, vuln.Path, "%s", vuln)
+		}
+	default:
+		r.check(CheckVulnerabilities, 20, true, fmt.Sprintf("none in %s", v.checkedText()))
+	}
+
+	sort.SliceStable(r.Findings, func(i, j int) bool {
+		return severityRank[r.Findings[i].Severity] < severityRank[r.Findings[j].Severity]
+	})
+
+	total, passed := 0, 0
+	for _, c := range r.Checks {
+		if c.Skipped {
+			continue
+		}
+		total += c.Weight
+		if c.Passed {
+			passed += c.Weight
+		}
+	}
+	if total > 0 {
+		r.Score = passed * 100 / total
+	}
+	switch {
+	case r.Score >= 80:
+		r.Level = "Strong"
+	case r.Score >= 50:
+		r.Level = "Moderate"
+	default:
+		r.Level = "Weak"
+	}
+	return r
+}
+
+func (r *SecurityReport) check(name string, weight int, passed bool, detail string) {
+	r.Checks = append(r.Checks, SecurityCheckResult{Name: name, Weight: weight, Passed: passed, Detail: detail})
+}
+
+// protectionRules summarizes p, e.g. "1 review, 2 status checks".
+func protectionRules(p *github.BranchProtection) string {
+	var rules []string
+	if p.RequiredReviews != nil && p.RequiredReviews.Count > 0 {
+		rules = append(rules, pluralize(p.RequiredReviews.Count, "review"))
+	}
+	if p.RequiredStatusChecks != nil && len(p.RequiredStatusChecks.Contexts) > 0 {
+		rules = append(rules, pluralize(len(p.RequiredStatusChecks.Contexts), "status check"))
+	}
+	if p.EnforceAdmins != nil && p.EnforceAdmins.Enabled {
+		rules = append(rules, "admins included")
+	}
+	if len(rules) == 0 {
+		return "no rules"
+	}
+	return strings.Join(rules, ", ")
+}
+
+func pluralize(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+func appendUnique(list []string, s string) []string {
+	for _, v := range list {
+		if v == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
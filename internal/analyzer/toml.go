@@ -0,0 +1,23 @@
+package analyzer
+
+import "github.com/BurntSushi/toml"
+
+// parseTOML decodes a pyproject.toml or Cargo.toml document. Tables, whether
+// written as [headers], dotted keys or inline, are map[string]interface{}
+// and arrays are []interface{}; tomlTable walks down to nested tables.
+func parseTOML(data []byte) (map[string]interface{}, error) {
+	doc := make(map[string]interface{})
+	if _, err := toml.Decode(string(data), &doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// tomlTable returns the table reached from table through keys, one level
+// each, or nil when one of them is missing or isn't a table.
+func tomlTable(table map[string]interface{}, keys ...string) map[string]interface{} {
+	for _, key := range keys {
+		table, _ = table[key].(map[string]interface{})
+	}
+	return table
+}
@@ -0,0 +1,165 @@
+package analyzer
+
+import (
+	"math"
+	"sort"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+// KeyPerson is someone the truck factor counts on. Files are the files they
+// are an author of.
+type KeyPerson struct {
+	Author string
+	Files  []string
+}
+
+type TruckFactorReport struct {
+	// Factor is how many people have to leave before more than half of the
+	// files have no author left; 0 when no file history was available.
+	Factor int
+	Risk   string
+	// Files counts the files whose authors are known and Commits the
+	// commits with changed files they were worked out from.
+	Files   int
+	Commits int
+	// KeyPeople are the people removed to reach Factor, in the order they
+	// were removed, and Orphaned the files left without an author then.
+	KeyPeople []KeyPerson
+	Orphaned  int
+}
+
+// fileHistory is who changed a file and who created it, if that is in the
+// history looked at.
+type fileHistory struct {
+	creator string
+	changes map[string]int
+}
+
+// TruckFactor works out who the authors of each file are from the changed
+// files of commits, newest first as GitHub lists them, and then removes the
+// author of the most files until more than half of the files are left
+// without one. Only the files in tree count, unless tree is empty. roster
+// names the authors of the commits and leaves out those of bots.
+//
+// The authors of a file are those with a degree of authorship (Fritz et
+// al.; Avelino et al., "A novel approach for estimating truck factors")
+// close to the highest one of the file: creating it and changing it raise
+// it, changes by others lower it.
+func TruckFactor(commits []github.Commit, tree []github.TreeEntry, roster *Roster) TruckFactorReport {
+	var r TruckFactorReport
+	histories := make(map[string]*fileHistory)
+	for i := len(commits) - 1; i >= 0; i-- {
+		c := &commits[i]
+		if len(c.Files) == 0 {
+			continue
+		}
+		r.Commits++
+		if c.IsMerge() || roster.Skip(c) {
+			continue
+		}
+		author := roster.Author(c)
+		for _, f := range c.Files {
+			switch f.Status {
+			case "removed":
+				delete(histories, f.Filename)
+				continue
+			case "renamed":
+				if h, ok := histories[f.PreviousFilename]; ok {
+					delete(histories, f.PreviousFilename)
+					histories[f.Filename] = h
+				}
+			}
+			h := histories[f.Filename]
+			if h == nil {
+				h = &fileHistory{changes: make(map[string]int)}
+				histories[f.Filename] = h
+			}
+			if f.Status == "added" {
+				h.creator = author
+			}
+			h.changes[author]++
+		}
+	}
+	if len(tree) > 0 {
+		current := make(map[string]bool, len(tree))
+		for _, e := range tree {
+			if e.Type == "blob" {
+				current[e.Path] = true
+			}
+		}
+		for p := range histories {
+			if !current[p] {
+				delete(histories, p)
+			}
+		}
+	}
+
+	authored := make(map[string][]string)
+	remaining := make(map[string]int)
+	for p, h := range histories {
+		for _, author := range h.authors() {
+			authored[author] = append(authored[author], p)
+			remaining[p]++
+		}
+	}
+	r.Files = len(remaining)
+	if r.Files == 0 {
+		r.Risk = "Unknown"
+		return r
+	}
+
+	for r.Orphaned*2 <= r.Files && len(authored) > 0 {
+		top := ""
+		for author, files := range authored {
+			if top == "" || len(files) > len(authored[top]) || len(files) == len(authored[top]) && author < top {
+				top = author
+			}
+		}
+		for _, p := range authored[top] {
+			remaining[p]--
+			if remaining[p] == 0 {
+				r.Orphaned++
+			}
+		}
+		sort.Strings(authored[top])
+		r.KeyPeople = append(r.KeyPeople, KeyPerson{Author: top, Files: authored[top]})
+		delete(authored, top)
+	}
+	r.Factor = len(r.KeyPeople)
+	switch {
+	case r.Factor == 1:
+		r.Risk = "High Risk"
+	case r.Factor == 2:
+		r.Risk = "Medium Risk"
+	default:
+		r.Risk = "Low Risk"
+	}
+	return r
+}
+
+// authors returns the people whose degree of authorship of the file is at
+// least 3.293 and 75% of the highest one, the thresholds of Avelino et al.
+func (h *fileHistory) authors() []string {
+	total := 0
+	for _, n := range h.changes {
+		total += n
+	}
+	doa := make(map[string]float64, len(h.changes))
+	highest := 0.0
+	for author, n := range h.changes {
+		first := 0.0
+		if author == h.creator {
+			first = 1
+		}
+		doa[author] = 3.293 + 1.098*first + 0.164*float64(n) - 0.321*math.Log(1+float64(total-n))
+		highest = math.Max(highest, doa[author])
+	}
+	var authors []string
+	for author, d := range doa {
+		if d >= 3.293 && d >= 0.75*highest {
+			authors = append(authors, author)
+		}
+	}
+	return authors
+}
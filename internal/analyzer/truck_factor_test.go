@@ -0,0 +1,154 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+// changeCommit is a commit by author changing files, given as "path" for a
+// modification or "status:path", with "renamed:old>new" for renames.
+func changeCommit(author string, files ...string) github.Commit {
+	c := github.Commit{Author: &github.User{Login: author}}
+	for _, f := range files {
+		status, p, ok := strings.Cut(f, ":")
+		if !ok {
+			status, p = "modified", f
+		}
+		file := github.CommitFile{Filename: p, Status: status}
+		if prev, next, ok := strings.Cut(p, ">"); ok {
+			file.PreviousFilename, file.Filename = prev, next
+		}
+		c.Files = append(c.Files, file)
+	}
+	return c
+}
+
+// newestFirst reverses commits given oldest first into GitHub's order.
+func newestFirst(commits ...github.Commit) []github.Commit {
+	for i, j := 0, len(commits)-1; i < j; i, j = i+1, j-1 {
+		commits[i], commits[j] = commits[j], commits[i]
+	}
+	return commits
+}
+
+func TestTruckFactor(t *testing.T) {
+	tests := []struct {
+		name      string
+		commits   []github.Commit
+		tree      []github.TreeEntry
+		want      int
+		wantRisk  string
+		wantFiles int
+		wantKey   []string
+	}{
+		{
+			name:     "no file history",
+			commits:  []github.Commit{{Author: &github.User{Login: "alice"}}},
+			want:     0,
+			wantRisk: "Unknown",
+		},
+		{
+			name: "single maintainer",
+			commits: newestFirst(
+				changeCommit("alice", "added:main.go", "added:go.mod"),
+				changeCommit("bob", "main.go"),
+				changeCommit("alice", "main.go"),
+			),
+			want:      1,
+			wantRisk:  "High Risk",
+			wantFiles: 2,
+			wantKey:   []string{"alice: go.mod, main.go"},
+		},
+		{
+			name: "split in two",
+			commits: newestFirst(
+				changeCommit("bob", "added:c.go", "added:d.go"),
+				changeCommit("alice", "added:a.go", "added:b.go"),
+			),
+			want:      2,
+			wantRisk:  "Medium Risk",
+			wantFiles: 4,
+			wantKey:   []string{"alice: a.go, b.go", "bob: c.go, d.go"},
+		},
+		{
+			name: "four owners",
+			commits: newestFirst(
+				changeCommit("alice", "added:a.go", "added:b.go"),
+				changeCommit("bob", "added:c.go", "added:d.go"),
+				changeCommit("carol", "added:e.go", "added:f.go"),
+				changeCommit("dave", "added:g.go", "added:h.go"),
+			),
+			want:      3,
+			wantRisk:  "Low Risk",
+			wantFiles: 8,
+			wantKey:   []string{"alice: a.go, b.go", "bob: c.go, d.go", "carol: e.go, f.go"},
+		},
+		{
+			name: "bots and aliases",
+			commits: newestFirst(
+				changeCommit("alice", "added:a.go", "added:b.go"),
+				changeCommit("dependabot[bot]", "added:go.sum"),
+				func() github.Commit {
+					c := changeCommit("", "added:c.go")
+					c.Author = nil
+					c.Commit.Author.Email = "1+alice@users.noreply.github.com"
+					return c
+				}(),
+				changeCommit("bob", "added:d.go"),
+			),
+			want:      1,
+			wantRisk:  "High Risk",
+			wantFiles: 4,
+			wantKey:   []string{"alice: a.go, b.go, c.go"},
+		},
+		{
+			name: "shared ownership",
+			commits: newestFirst(
+				changeCommit("alice", "added:a.go", "added:b.go", "added:old.go", "added:gone.go"),
+				changeCommit("bob", "added:c.go", "added:d.go"),
+				changeCommit("carol", "added:e.go", "added:f.go"),
+				changeCommit("dave", "added:g.go", "added:h.go", "added:tmp.go"),
+				// Dave renames old.go and changes it three more times, but
+				// Alice, who created it, still counts as its author too.
+				// gone.go goes and tmp.go isn't in the tree.
+				changeCommit("dave", "renamed:old.go>new.go", "removed:gone.go"),
+				changeCommit("dave", "new.go"),
+				changeCommit("dave", "new.go"),
+				changeCommit("dave", "new.go"),
+			),
+			tree: func() []github.TreeEntry {
+				var tree []github.TreeEntry
+				for _, p := range []string{"a.go", "b.go", "c.go", "d.go", "e.go", "f.go", "g.go", "h.go", "new.go"} {
+					tree = append(tree, github.TreeEntry{Path: p, Type: "blob"})
+				}
+				return tree
+			}(),
+			want:      2,
+			wantRisk:  "Medium Risk",
+			wantFiles: 9,
+			wantKey:   []string{"alice: a.go, b.go, new.go", "dave: g.go, h.go, new.go"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := TruckFactor(tt.commits, tt.tree, NewIdentities().Roster(tt.commits))
+			if r.Factor != tt.want || r.Risk != tt.wantRisk || r.Files != tt.wantFiles {
+				t.Errorf("truck factor %d (%s) over %d files, want %d (%s) over %d", r.Factor, r.Risk, r.Files, tt.want, tt.wantRisk, tt.wantFiles)
+			}
+			var key []string
+			for _, p := range r.KeyPeople {
+				key = append(key, fmt.Sprintf("%s: %s", p.Author, strings.Join(p.Files, ", ")))
+			}
+			if strings.Join(key, "\n") != strings.Join(tt.wantKey, "\n") {
+				t.Errorf("key people:\n%s\nwant:\n%s", strings.Join(key, "\n"), strings.Join(tt.wantKey, "\n"))
+			}
+			if r.Factor > 0 && r.Orphaned*2 <= r.Files {
+				t.Errorf("only %d of %d files orphaned", r.Orphaned, r.Files)
+			}
+		})
+	}
+}
@@ -0,0 +1,464 @@
+package analyzer
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// osvEntry is the part of an OSV advisory Repo-lyzer reads; see
+// https://ossf.github.io/osv-schema/.
+type osvEntry struct {
+	ID        string   `json:"id"`
+	Aliases   []string `json:"aliases"`
+	Summary   string   `json:"summary"`
+	Withdrawn string   `json:"withdrawn"`
+	Severity  []struct {
+		Type  string `json:"type"`
+		Score string `json:"score"`
+	} `json:"severity"`
+	Affected []struct {
+		Package struct {
+			Ecosystem string `json:"ecosystem"`
+			Name      string `json:"name"`
+		} `json:"package"`
+		Ranges []struct {
+			Type   string              `json:"type"`
+			Events []map[string]string `json:"events"`
+		} `json:"ranges"`
+		Versions []string `json:"versions"`
+	} `json:"affected"`
+	DatabaseSpecific map[string]interface{} `json:"database_specific"`
+}
+
+// OSVDatabase is a local snapshot of OSV advisories, indexed by the
+// packages they affect.
+type OSVDatabase struct {
+	Source     string
+	Advisories int
+	byPackage  map[[2]string][]*osvEntry
+}
+
+// LoadOSV loads the OSV advisories in the JSON files of a directory, at any
+// depth, or of a zip file like the all.zip exports of osv.dev. Withdrawn
+// advisories are left out.
+func LoadOSV(path string) (*OSVDatabase, error) {
+	db := &OSVDatabase{Source: path, byPackage: make(map[[2]string][]*osvEntry)}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading OSV database: %w", err)
+	}
+
+	var fsys fs.FS
+	if info.IsDir() {
+		fsys = os.DirFS(path)
+	} else {
+		zr, err := zip.OpenReader(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading OSV database: %w", err)
+		}
+		defer zr.Close()
+		fsys = zr
+	}
+
+	err = fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.EqualFold(filepath.Ext(p), ".json") {
+			return err
+		}
+		f, err := fsys.Open(p)
+		if err != nil {
+			return err
+		}
+		data, err := io.ReadAll(f)
+		f.Close()
+		if err != nil {
+			return err
+		}
+		var e osvEntry
+		if err := json.Unmarshal(data, &e); err != nil {
+			return fmt.Errorf("parsing %s: %w", p, err)
+		}
+		db.add(&e)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("reading OSV database %s: %w", path, err)
+	}
+	return db, nil
+}
+
+func (db *OSVDatabase) add(e *osvEntry) {
+	if e.ID == "" || e.Withdrawn != "" {
+		return
+	}
+	db.Advisories++
+	seen := make(map[[2]string]bool)
+	for _, a := range e.Affected {
+		key := [2]string{a.Package.Ecosystem, packageKey(a.Package.Ecosystem, a.Package.Name)}
+		if !seen[key] {
+			seen[key] = true
+			db.byPackage[key] = append(db.byPackage[key], e)
+		}
+	}
+}
+
+var pypiSeparators = regexp.MustCompile(`[-_.]+`)
+
+// packageKey normalizes name the way ecosystem compares package names.
+func packageKey(ecosystem, name string) string {
+	if ecosystem == EcosystemPyPI {
+		return pypiSeparators.ReplaceAllString(strings.ToLower(name), "-")
+	}
+	return name
+}
+
+// Vulnerability is an advisory affecting the version of a dependency a
+// manifest names. Fixed is the first version the advisory is fixed in, if
+// there is one.
+type Vulnerability struct {
+	Ecosystem string
+	Package   string
+	Version   string
+	Path      string
+	ID        string
+	Aliases   []string
+	Summary   string
+	Severity  Severity
+	Fixed     string
+}
+
+// String describes v on one line, e.g. "lodash@4.17.20 is affected by
+// GHSA-35jh-r3h4-6jhm (fixed in 4.17.21)".
+func (v Vulnerability) String() string {
+	s := fmt.Sprintf("%s@%s is affected by %s", v.Package, v.Version, v.ID)
+	if v.Fixed != "" {
+		s += fmt.Sprintf(" (fixed in %s)", v.Fixed)
+	}
+	return s
+}
+
+type VulnerabilityReport struct {
+	Source     string
+	Advisories int
+	// Checked counts the dependencies with an exact version, the only ones
+	// that can be matched.
+	Checked int
+	// Unchecked counts the packages no manifest gives an exact version of,
+	// like ranges without a lockfile, which were left out.
+	Unchecked       int
+	Vulnerabilities []Vulnerability // most severe first
+}
+
+// checkedText says how many dependencies were checked, and how many were
+// not, e.g. "4 checked dependencies (3 more without an exact version not
+// checked)".
+func (r VulnerabilityReport) checkedText() string {
+	s := fmt.Sprintf("%d checked dependencies", r.Checked)
+	if r.Unchecked > 0 {
+		s += fmt.Sprintf(" (%d more without an exact version not checked)", r.Unchecked)
+	}
+	return s
+}
+
+// Packages counts the distinct packages with vulnerabilities.
+func (r VulnerabilityReport) Packages() int {
+	seen := make(map[[3]string]bool)
+	for _, v := range r.Vulnerabilities {
+		seen[[3]string{v.Ecosystem, v.Package, v.Version}] = true
+	}
+	return len(seen)
+}
+
+// Match looks up the dependencies of r with an exact version in db. A
+// dependency listed by several manifests, like a package.json and its
+// lockfile, is reported once, and counts as unchecked only when none of
+// them gives its exact version.
+func (db *OSVDatabase) Match(r DependencyReport) VulnerabilityReport {
+	report := VulnerabilityReport{Source: db.Source, Advisories: db.Advisories}
+	checked := make(map[[3]string]bool)
+	checkedPackages, unchecked := make(map[[2]string]bool), make(map[[2]string]bool)
+	for _, m := range r.Manifests {
+		for _, d := range m.Dependencies {
+			version, ok := exactVersion(d)
+			key := [3]string{m.Ecosystem, packageKey(m.Ecosystem, d.Name), version}
+			if !ok {
+				unchecked[[2]string{key[0], key[1]}] = true
+				continue
+			}
+			if checked[key] {
+				continue
+			}
+			checked[key] = true
+			checkedPackages[[2]string{key[0], key[1]}] = true
+			report.Checked++
+			for _, e := range db.byPackage[[2]string{key[0], key[1]}] {
+				fixed, affected := e.affects(key[0], key[1], version)
+				if !affected {
+					continue
+				}
+				report.Vulnerabilities = append(report.Vulnerabilities, Vulnerability{
+					Ecosystem: m.Ecosystem,
+					Package:   d.Name,
+					Version:   version,
+					Path:      m.Path,
+					ID:        e.ID,
+					Aliases:   e.Aliases,
+					Summary:   e.Summary,
+					Severity:  e.severity(),
+					Fixed:     fixed,
+				})
+			}
+		}
+	}
+	for pkg := range unchecked {
+		if !checkedPackages[pkg] {
+			report.Unchecked++
+		}
+	}
+	sort.SliceStable(report.Vulnerabilities, func(i, j int) bool {
+		a, b := report.Vulnerabilities[i], report.Vulnerabilities[j]
+		if severityRank[a.Severity] != severityRank[b.Severity] {
+			return severityRank[a.Severity] < severityRank[b.Severity]
+		}
+		return a.Package < b.Package
+	})
+	return report
+}
+
+// exactVersion returns the single version d is pinned to, without the
+// operators and prefixes manifests write it with.
+func exactVersion(d Dependency) (string, bool) {
+	if !d.Pinned {
+		return "", false
+	}
+	v := strings.TrimLeft(d.Version, "=v")
+	v = strings.TrimSuffix(v, "+incompatible")
+	if v == "" || strings.ContainsAny(v, " <>~^*,:/@$[]()") {
+		return "", false
+	}
+	return v, true
+}
+
+// affects reports whether e affects version of the named package, and the
+// version that fixes it.
+func (e *osvEntry) affects(ecosystem, name, version string) (string, bool) {
+	for _, a := range e.Affected {
+		if a.Package.Ecosystem != ecosystem || packageKey(ecosystem, a.Package.Name) != name {
+			continue
+		}
+		listed := false
+		for _, v := range a.Versions {
+			if compareVersions(v, version) == 0 {
+				listed = true
+			}
+		}
+		for _, rng := range a.Ranges {
+			if rng.Type != "SEMVER" && rng.Type != "ECOSYSTEM" {
+				continue
+			}
+			if fixed, ok := inRange(rng.Events, version); ok {
+				return fixed, true
+			}
+		}
+		if listed {
+			return "", true
+		}
+	}
+	return "", false
+}
+
+// inRange evaluates the events of an OSV range for version: it is affected
+// from an introduced version up to the next fixed version, or through the
+// next last_affected one.
+func inRange(events []map[string]string, version string) (string, bool) {
+	type event struct{ kind, version string }
+	var sorted []event
+	for _, ev := range events {
+		for kind, v := range ev {
+			sorted = append(sorted, event{kind, v})
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return compareVersions(sorted[i].version, sorted[j].version) < 0
+	})
+
+	// Events up to version decide whether it is affected; they don't
+	// change after it, where the first fix is the one that applies.
+	affected, fixed := false, ""
+	for _, ev := range sorted {
+		c := compareVersions(version, ev.version)
+		switch {
+		case ev.kind == "introduced" && c >= 0:
+			affected = true
+		case ev.kind == "fixed" && c >= 0, ev.kind == "last_affected" && c > 0:
+			affected = false
+		case ev.kind == "fixed" && affected && fixed == "":
+			fixed = ev.version
+		}
+	}
+	if !affected {
+		return "", false
+	}
+	return fixed, true
+}
+
+var versionPart = regexp.MustCompile(`\d+|[A-Za-z]+`)
+
+// compareVersions compares two versions of any ecosystem closely enough for
+// advisories: numbers compare numerically and words alphabetically, and a
+// word after the release numbers, like "rc1" in "1.0.0-rc1", makes a
+// pre-release that comes before the release.
+func compareVersions(a, b string) int {
+	pa := versionPart.FindAllString(strings.TrimPrefix(a, "v"), -1)
+	pb := versionPart.FindAllString(strings.TrimPrefix(b, "v"), -1)
+	for i := 0; i < len(pa) || i < len(pb); i++ {
+		switch {
+		case i >= len(pa):
+			return releaseOrder(pb[i])
+		case i >= len(pb):
+			return -releaseOrder(pa[i])
+		}
+		na, errA := strconv.Atoi(pa[i])
+		nb, errB := strconv.Atoi(pb[i])
+		switch {
+		case errA == nil && errB == nil:
+			if na != nb {
+				return sign(na - nb)
+			}
+		case errA == nil:
+			return 1
+		case errB == nil:
+			return -1
+		default:
+			if c := strings.Compare(strings.ToLower(pa[i]), strings.ToLower(pb[i])); c != 0 {
+				return c
+			}
+		}
+	}
+	return 0
+}
+
+// releaseOrder compares a version with the same version with part added:
+// a number makes it later, a word a pre-release.
+func releaseOrder(part string) int {
+	if _, err := strconv.Atoi(part); err == nil {
+		return -1
+	}
+	return 1
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
+
+// severity is the severity the advisory's database gives it, or else the
+// rating of its CVSS v3 score.
+func (e *osvEntry) severity() Severity {
+	if s, ok := e.DatabaseSpecific["severity"].(string); ok {
+		switch strings.ToUpper(s) {
+		case "CRITICAL":
+			return SeverityCritical
+		case "HIGH":
+			return SeverityHigh
+		case "MODERATE", "MEDIUM":
+			return SeverityMedium
+		case "LOW":
+			return SeverityLow
+		}
+	}
+	for _, s := range e.Severity {
+		if s.Type != "CVSS_V3" {
+			continue
+		}
+		switch score := cvss3Score(s.Score); {
+		case score >= 9:
+			return SeverityCritical
+		case score >= 7:
+			return SeverityHigh
+		case score >= 4:
+			return SeverityMedium
+		case score > 0:
+			return SeverityLow
+		}
+	}
+	return SeverityUnknown
+}
+
+// cvss3Weights are the CVSS v3 base metric values; privileges required
+// weigh more when the scope changes.
+var cvss3Weights = map[string]map[string]float64{
+	"AV": {"N": 0.85, "A": 0.62, "L": 0.55, "P": 0.2},
+	"AC": {"L": 0.77, "H": 0.44},
+	"PR": {"N": 0.85, "L": 0.62, "H": 0.27},
+	"UI": {"N": 0.85, "R": 0.62},
+	"C":  {"H": 0.56, "L": 0.22, "N": 0},
+	"I":  {"H": 0.56, "L": 0.22, "N": 0},
+	"A":  {"H": 0.56, "L": 0.22, "N": 0},
+}
+
+// cvss3Score computes the base score of a CVSS v3 vector like
+// "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H", or 0 if it is malformed.
+func cvss3Score(vector string) float64 {
+	metrics := make(map[string]string)
+	for _, part := range strings.Split(vector, "/") {
+		if k, v, ok := strings.Cut(part, ":"); ok {
+			metrics[k] = v
+		}
+	}
+	values := make(map[string]float64)
+	for metric, weights := range cvss3Weights {
+		v, ok := weights[metrics[metric]]
+		if !ok {
+			return 0
+		}
+		values[metric] = v
+	}
+	changed := metrics["S"] == "C"
+	if changed {
+		switch metrics["PR"] {
+		case "L":
+			values["PR"] = 0.68
+		case "H":
+			values["PR"] = 0.5
+		}
+	}
+
+	iss := 1 - (1-values["C"])*(1-values["I"])*(1-values["A"])
+	impact := 6.42 * iss
+	if changed {
+		impact = 7.52*(iss-0.029) - 3.25*math.Pow(iss-0.02, 15)
+	}
+	if impact <= 0 {
+		return 0
+	}
+	exploitability := 8.22 * values["AV"] * values["AC"] * values["PR"] * values["UI"]
+	if changed {
+		return roundUp(math.Min(1.08*(impact+exploitability), 10))
+	}
+	return roundUp(math.Min(impact+exploitability, 10))
+}
+
+// roundUp rounds x up to one decimal the way CVSS v3.1 specifies, without
+// floating point surprises.
+func roundUp(x float64) float64 {
+	n := int(math.Round(x * 100000))
+	if n%10000 == 0 {
+		return float64(n) / 100000
+	}
+	return float64(n/10000+1) / 10
+}
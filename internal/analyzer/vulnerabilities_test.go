@@ -0,0 +1,197 @@
+package analyzer
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// osvAdvisories are OSV entries for made-up packages, by file name.
+var osvAdvisories = map[string]string{
+	"Go/EX-2024-0001.json": `{
+		"id": "EX-2024-0001",
+		"summary": "Path traversal in router",
+		"affected": [{
+			"package": {"ecosystem": "Go", "name": "example.com/router"},
+			"ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}, {"fixed": "1.5.6"}, {"introduced": "2.0.0"}, {"fixed": "2.1.0"}]}]
+		}],
+		"database_specific": {"severity": "HIGH"}
+	}`,
+	"PyPI/EX-2024-0002.json": `{
+		"id": "EX-2024-0002",
+		"aliases": ["CVE-2024-0002"],
+		"affected": [{
+			"package": {"ecosystem": "PyPI", "name": "Foo_Bar"},
+			"ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "1.0"}, {"last_affected": "1.2"}]}]
+		}],
+		"severity": [{"type": "CVSS_V3", "score": "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"}]
+	}`,
+	"npm/EX-2024-0003.json": `{
+		"id": "EX-2024-0003",
+		"affected": [{"package": {"ecosystem": "npm", "name": "left-pad"}, "versions": ["1.3.0"]}],
+		"database_specific": {"cwe_ids": ["CWE-400"]}
+	}`,
+	"npm/EX-2024-0004.json": `{
+		"id": "EX-2024-0004",
+		"withdrawn": "2024-05-01T00:00:00Z",
+		"affected": [{"package": {"ecosystem": "npm", "name": "left-pad"}, "versions": ["1.3.0"]}]
+	}`,
+}
+
+func writeOSVDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range osvAdvisories {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func writeOSVZip(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "all.zip")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(f)
+	for name, content := range osvAdvisories {
+		w, err := zw.Create(filepath.Base(name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadOSV(t *testing.T) {
+	bad := t.TempDir()
+	if err := os.WriteFile(filepath.Join(bad, "broken.json"), []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		wantErr string
+	}{
+		{"directory", writeOSVDir(t), ""},
+		{"zip", writeOSVZip(t), ""},
+		{"malformed advisory", bad, "parsing broken.json"},
+		{"missing", filepath.Join(bad, "nope.zip"), "reading OSV database"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, err := LoadOSV(tt.path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadOSV: %v", err)
+			}
+			if db.Advisories != 3 || db.Source != tt.path {
+				t.Errorf("loaded %d advisories from %s, want 3 (one is withdrawn)", db.Advisories, db.Source)
+			}
+		})
+	}
+}
+
+func TestOSVDatabaseMatch(t *testing.T) {
+	db, err := LoadOSV(writeOSVDir(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	deps := AnalyzeDependencies(map[string][]byte{
+		"go.mod":           []byte("module m\n\nrequire (\n\texample.com/router v1.5.5\n\texample.com/other v1.0.0\n)\n"),
+		"svc/go.mod":       []byte("module s\n\nrequire example.com/router v2.1.0\n"),
+		"requirements.txt": []byte("foo.bar==1.2\nFOO-BAR>=1.0\nbaz==1.2.1\nqux>=2.0\n"),
+		"web/package.json": []byte(`{"dependencies": {"left-pad": "1.3.0"}}`),
+		"web/package-lock.json": []byte(`{"lockfileVersion": 3, "packages": {
+			"": {"dependencies": {"left-pad": "1.3.0"}},
+			"node_modules/left-pad": {"version": "1.3.0"}
+		}}`),
+	})
+
+	r := db.Match(deps)
+	var got []string
+	for _, v := range r.Vulnerabilities {
+		got = append(got, string(v.Severity)+" "+v.Path+" "+v.String())
+	}
+	want := []string{
+		"critical requirements.txt foo.bar@1.2 is affected by EX-2024-0002",
+		"high go.mod example.com/router@1.5.5 is affected by EX-2024-0001 (fixed in 1.5.6)",
+		"unknown web/package-lock.json left-pad@1.3.0 is affected by EX-2024-0003",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("vulnerabilities:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	// router twice, other, foo.bar, baz and left-pad once; FOO-BAR floats
+	// but foo.bar is checked, which leaves qux.
+	if r.Checked != 6 || r.Unchecked != 1 || r.Packages() != 3 || r.Advisories != 3 {
+		t.Errorf("checked %d, unchecked %d, %d packages, %d advisories", r.Checked, r.Unchecked, r.Packages(), r.Advisories)
+	}
+	if got, want := r.checkedText(), "6 checked dependencies (1 more without an exact version not checked)"; got != want {
+		t.Errorf("checkedText() = %q, want %q", got, want)
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.2.3", "1.2.3", 0},
+		{"v1.10.0", "1.9.9", 1},
+		{"1.0.0-rc1", "1.0.0", -1},
+		{"1.0.0-rc.2", "1.0.0-rc.10", -1},
+		{"1.0", "1.0.1", -1},
+		{"2.0b1", "2.0a3", 1},
+		{"0", "0.0.1", -1},
+		{"0.0.0-20240101000000-abcdef", "0.0.0-20230101000000-abcdef", 1},
+	}
+	for _, tt := range tests {
+		if got := compareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestCVSS3Score(t *testing.T) {
+	tests := []struct {
+		vector string
+		want   float64
+	}{
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H", 9.8},
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:R/S:C/C:L/I:L/A:N", 6.1},
+		{"CVSS:3.0/AV:L/AC:H/PR:H/UI:R/S:U/C:L/I:N/A:N", 1.8},
+		{"CVSS:3.1/AV:N/AC:L/PR:L/UI:N/S:C/C:H/I:H/A:H", 9.9},
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:N/I:N/A:N", 0},
+		{"CVSS:3.1/AV:X", 0},
+	}
+	for _, tt := range tests {
+		if got := cvss3Score(tt.vector); got != tt.want {
+			t.Errorf("cvss3Score(%s) = %v, want %v", tt.vector, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,346 @@
+package github
+
+import (
+	"bufio"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// Credentials supply the token a Client authenticates with.
+type Credentials interface {
+	// Token returns the token to send. It is called for every request,
+	// so implementations that fetch tokens must cache them.
+	Token(ctx context.Context) (string, error)
+
+	// Source describes where the token comes from, e.g. "GH_TOKEN".
+	Source() string
+}
+
+// A Resolver looks for credentials in one place, for the GitHub host c
+// talks to. It returns nil credentials if it finds none there, and an error
+// only if the place is configured but unusable.
+type Resolver func(c *Client) (Credentials, error)
+
+// DefaultResolvers is the chain NewClient tries unless WithCredentials
+// replaces it: the GITHUB_TOKEN and GH_TOKEN environment variables, the gh
+// CLI's hosts.yml, ~/.netrc, and a GitHub App configured through the
+// environment (see FromAppEnv).
+func DefaultResolvers() []Resolver {
+	return []Resolver{
+		FromEnv("GITHUB_TOKEN", "GH_TOKEN"),
+		FromGHConfig(),
+		FromNetrc(),
+		FromAppEnv(),
+	}
+}
+
+// resolveCredentials returns the credentials of the first resolver that has
+// any, or of all of them if pooled.
+func resolveCredentials(c *Client, resolvers []Resolver, pooled bool) ([]Credentials, error) {
+	var found []Credentials
+	for _, resolve := range resolvers {
+		creds, err := resolve(c)
+		if err != nil {
+			return nil, err
+		}
+		if creds == nil {
+			continue
+		}
+		found = append(found, creds)
+		if !pooled {
+			break
+		}
+	}
+	return found, nil
+}
+
+// staticToken is a fixed token.
+type staticToken struct {
+	token, source string
+}
+
+func (s staticToken) Token(ctx context.Context) (string, error) { return s.token, nil }
+func (s staticToken) Source() string                            { return s.source }
+
+// FromToken uses token, described as source, if it is not empty.
+func FromToken(token, source string) Resolver {
+	return func(c *Client) (Credentials, error) {
+		if token == "" {
+			return nil, nil
+		}
+		return staticToken{token: token, source: source}, nil
+	}
+}
+
+// FromEnv uses the first of the named environment variables that is set.
+func FromEnv(names ...string) Resolver {
+	return func(c *Client) (Credentials, error) {
+		for _, name := range names {
+			if token := os.Getenv(name); token != "" {
+				return staticToken{token: token, source: name}, nil
+			}
+		}
+		return nil, nil
+	}
+}
+
+// FromGHConfig uses the token the gh CLI stored for the client's host in
+// its hosts.yml. Tokens gh keeps in the system keyring are not visible
+// there and are skipped.
+func FromGHConfig() Resolver {
+	return func(c *Client) (Credentials, error) {
+		path := ghHostsPath()
+		data, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var hosts map[string]struct {
+			OAuthToken string `yaml:"oauth_token"`
+		}
+		if err := yaml.Unmarshal(data, &hosts); err != nil {
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+		if token := hosts[c.host()].OAuthToken; token != "" {
+			return staticToken{token: token, source: "gh CLI (" + path + ")"}, nil
+		}
+		return nil, nil
+	}
+}
+
+// ghHostsPath locates hosts.yml the way gh does.
+func ghHostsPath() string {
+	if dir := os.Getenv("GH_CONFIG_DIR"); dir != "" {
+		return filepath.Join(dir, "hosts.yml")
+	}
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "gh", "hosts.yml")
+	}
+	if dir := os.Getenv("AppData"); dir != "" {
+		return filepath.Join(dir, "GitHub CLI", "hosts.yml")
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".config", "gh", "hosts.yml")
+}
+
+// FromNetrc uses the password of the client's host in the netrc file named
+// by $NETRC, or ~/.netrc. For github.com both api.github.com and github.com
+// entries match.
+func FromNetrc() Resolver {
+	return func(c *Client) (Credentials, error) {
+		path := os.Getenv("NETRC")
+		if path == "" {
+			home, _ := os.UserHomeDir()
+			path = filepath.Join(home, ".netrc")
+		}
+		f, err := os.Open(path)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+
+		machines, err := parseNetrc(f)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+		hosts := []string{c.host()}
+		if c.host() == "github.com" {
+			hosts = []string{"api.github.com", "github.com"}
+		}
+		for _, host := range hosts {
+			if token := machines[host]; token != "" {
+				return staticToken{token: token, source: path}, nil
+			}
+		}
+		return nil, nil
+	}
+}
+
+// parseNetrc maps each machine in a netrc file to its password. Macros are
+// not supported.
+func parseNetrc(f *os.File) (map[string]string, error) {
+	machines := make(map[string]string)
+	scanner := bufio.NewScanner(f)
+	scanner.Split(bufio.ScanWords)
+
+	var machine string
+	for scanner.Scan() {
+		switch scanner.Text() {
+		case "machine":
+			if !scanner.Scan() {
+				return machines, errors.New("machine without a name")
+			}
+			machine = scanner.Text()
+		case "default":
+			machine = ""
+		case "password":
+			if !scanner.Scan() {
+				return machines, errors.New("password without a value")
+			}
+			if machine != "" {
+				machines[machine] = scanner.Text()
+			}
+		}
+	}
+	return machines, scanner.Err()
+}
+
+// appTokenSkew is how long before it expires an installation token is
+// replaced, and how far back JWTs are dated to allow for clock drift.
+const appTokenSkew = time.Minute
+
+// appCredentials exchange a GitHub App's signed JWT for installation tokens.
+type appCredentials struct {
+	client         *Client
+	appID          int64
+	installationID int64
+	key            *rsa.PrivateKey
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+// FromApp authenticates as installation installationID of GitHub App
+// appID, signing with the app's PEM-encoded private key.
+func FromApp(appID, installationID int64, keyPEM []byte) Resolver {
+	return func(c *Client) (Credentials, error) {
+		key, err := parseAppKey(keyPEM)
+		if err != nil {
+			return nil, err
+		}
+		return &appCredentials{client: c, appID: appID, installationID: installationID, key: key}, nil
+	}
+}
+
+// FromAppEnv configures a GitHub App from GITHUB_APP_ID,
+// GITHUB_APP_INSTALLATION_ID and GITHUB_APP_PRIVATE_KEY_PATH. It finds
+// nothing unless GITHUB_APP_ID is set.
+func FromAppEnv() Resolver {
+	return func(c *Client) (Credentials, error) {
+		if os.Getenv("GITHUB_APP_ID") == "" {
+			return nil, nil
+		}
+		appID, err := strconv.ParseInt(os.Getenv("GITHUB_APP_ID"), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("GITHUB_APP_ID: %w", err)
+		}
+		installationID, err := strconv.ParseInt(os.Getenv("GITHUB_APP_INSTALLATION_ID"), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("GITHUB_APP_INSTALLATION_ID: %w", err)
+		}
+		keyPEM, err := os.ReadFile(os.Getenv("GITHUB_APP_PRIVATE_KEY_PATH"))
+		if err != nil {
+			return nil, fmt.Errorf("GITHUB_APP_PRIVATE_KEY_PATH: %w", err)
+		}
+		return FromApp(appID, installationID, keyPEM)(c)
+	}
+}
+
+func (a *appCredentials) Source() string {
+	return fmt.Sprintf("GitHub App %d (installation %d)", a.appID, a.installationID)
+}
+
+func (a *appCredentials) Token(ctx context.Context) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.token != "" && time.Until(a.expiresAt) > appTokenSkew {
+		return a.token, nil
+	}
+
+	jwt, err := a.jwt(time.Now())
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST",
+		a.client.endpoint("app/installations/%d/access_tokens", a.installationID), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+jwt)
+
+	var resp struct {
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	if _, err := a.client.do(req, &resp); err != nil {
+		return "", fmt.Errorf("fetching installation token: %w", err)
+	}
+	a.token, a.expiresAt = resp.Token, resp.ExpiresAt
+	return a.token, nil
+}
+
+// jwt signs the short-lived RS256 token that identifies the app itself.
+func (a *appCredentials) jwt(now time.Time) (string, error) {
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	claims, _ := json.Marshal(map[string]interface{}{
+		"iat": now.Add(-appTokenSkew).Unix(),
+		"exp": now.Add(9 * time.Minute).Unix(),
+		"iss": strconv.FormatInt(a.appID, 10),
+	})
+
+	enc := base64.RawURLEncoding
+	signed := enc.EncodeToString(header) + "." + enc.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(signed))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, a.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", err
+	}
+	return signed + "." + enc.EncodeToString(sig), nil
+}
+
+// parseAppKey decodes a PKCS#1 or PKCS#8 RSA private key as downloaded from
+// the app's settings page.
+func parseAppKey(keyPEM []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return nil, errors.New("GitHub App private key is not PEM encoded")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parsing GitHub App private key: %w", err)
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("GitHub App private key is not an RSA key")
+	}
+	return rsaKey, nil
+}
+
+// host is the GitHub host the client talks to, as gh and netrc name it:
+// github.com for the public API, the server's hostname otherwise.
+func (c *Client) host() string {
+	u, err := url.Parse(c.baseURL)
+	if err != nil || u.Hostname() == "api.github.com" {
+		return "github.com"
+	}
+	return u.Hostname()
+}
@@ -0,0 +1,252 @@
+package github_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+// authServer serves a repository and records the Authorization header of
+// each request for it.
+type authServer struct {
+	*httptest.Server
+
+	mu   sync.Mutex
+	seen []string
+}
+
+func newAuthServer(t *testing.T, handler http.HandlerFunc) *authServer {
+	s := &authServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/repos/o/r" {
+			s.mu.Lock()
+			s.seen = append(s.seen, r.Header.Get("Authorization"))
+			s.mu.Unlock()
+			w.Write([]byte(`{"full_name": "o/r"}`))
+			return
+		}
+		if handler != nil {
+			handler(w, r)
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// isolateAuth clears every ambient credential source for the test.
+func isolateAuth(t *testing.T) string {
+	dir := t.TempDir()
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("GH_TOKEN", "")
+	t.Setenv("GITHUB_APP_ID", "")
+	t.Setenv("GH_CONFIG_DIR", dir)
+	t.Setenv("NETRC", filepath.Join(dir, "netrc"))
+	return dir
+}
+
+func TestCredentialChain(t *testing.T) {
+	tests := []struct {
+		name       string
+		setup      func(t *testing.T, dir string)
+		resolvers  []github.Resolver
+		wantSource string
+		wantHeader string
+	}{
+		{
+			name:       "none",
+			wantSource: "",
+			wantHeader: "",
+		},
+		{
+			name: "explicit token wins",
+			setup: func(t *testing.T, dir string) {
+				t.Setenv("GITHUB_TOKEN", "env-token")
+			},
+			resolvers:  append([]github.Resolver{github.FromToken("flag-token", "--token")}, github.DefaultResolvers()...),
+			wantSource: "--token",
+			wantHeader: "Bearer flag-token",
+		},
+		{
+			name: "GITHUB_TOKEN before GH_TOKEN",
+			setup: func(t *testing.T, dir string) {
+				t.Setenv("GITHUB_TOKEN", "github-token")
+				t.Setenv("GH_TOKEN", "gh-token")
+			},
+			wantSource: "GITHUB_TOKEN",
+			wantHeader: "Bearer github-token",
+		},
+		{
+			name: "GH_TOKEN",
+			setup: func(t *testing.T, dir string) {
+				t.Setenv("GH_TOKEN", "gh-token")
+			},
+			wantSource: "GH_TOKEN",
+			wantHeader: "Bearer gh-token",
+		},
+		{
+			name: "gh hosts.yml",
+			setup: func(t *testing.T, dir string) {
+				writeFile(t, filepath.Join(dir, "hosts.yml"),
+					"github.com:\n    oauth_token: wrong-host\n127.0.0.1:\n    user: octocat\n    oauth_token: gho_hosts\n")
+			},
+			wantSource: "gh CLI",
+			wantHeader: "Bearer gho_hosts",
+		},
+		{
+			name: "netrc",
+			setup: func(t *testing.T, dir string) {
+				writeFile(t, filepath.Join(dir, "netrc"),
+					"machine example.com login x password wrong\nmachine 127.0.0.1\n  login octocat\n  password netrc-token\n")
+			},
+			wantSource: "netrc",
+			wantHeader: "Bearer netrc-token",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := isolateAuth(t)
+			if tt.setup != nil {
+				tt.setup(t, dir)
+			}
+			srv := newAuthServer(t, nil)
+
+			opts := []github.Option{github.WithBaseURL(srv.URL)}
+			if tt.resolvers != nil {
+				opts = append(opts, github.WithCredentials(tt.resolvers...))
+			}
+			client := github.NewClient(opts...)
+
+			if got := client.AuthSource(); !strings.Contains(got, tt.wantSource) || (tt.wantSource == "") != (got == "") {
+				t.Errorf("AuthSource() = %q, want %q", got, tt.wantSource)
+			}
+			if _, err := client.GetRepo(context.Background(), "o", "r"); err != nil {
+				t.Fatalf("GetRepo: %v", err)
+			}
+			if srv.seen[0] != tt.wantHeader {
+				t.Errorf("Authorization = %q, want %q", srv.seen[0], tt.wantHeader)
+			}
+		})
+	}
+}
+
+func TestAppCredentials(t *testing.T) {
+	isolateAuth(t)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	exchanges := 0
+	srv := newAuthServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/app/installations/42/access_tokens" {
+			http.NotFound(w, r)
+			return
+		}
+		exchanges++
+		if iss, err := verifyJWT(&key.PublicKey, strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")); err != nil || iss != "7" {
+			t.Errorf("app JWT: iss %q, %v", iss, err)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"token":      "ghs_installation",
+			"expires_at": time.Now().Add(time.Hour),
+		})
+	})
+
+	client := github.NewClient(github.WithBaseURL(srv.URL), github.WithCredentials(github.FromApp(7, 42, keyPEM)))
+	if got := client.AuthSource(); got != "GitHub App 7 (installation 42)" {
+		t.Errorf("AuthSource() = %q", got)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetRepo(context.Background(), "o", "r"); err != nil {
+			t.Fatalf("GetRepo: %v", err)
+		}
+	}
+	if exchanges != 1 {
+		t.Errorf("exchanged the JWT %d times, want once", exchanges)
+	}
+	for _, header := range srv.seen {
+		if header != "Bearer ghs_installation" {
+			t.Errorf("Authorization = %q", header)
+		}
+	}
+}
+
+func TestUnusableCredentials(t *testing.T) {
+	isolateAuth(t)
+	srv := newAuthServer(t, nil)
+
+	client := github.NewClient(github.WithBaseURL(srv.URL), github.WithCredentials(github.FromApp(7, 42, []byte("not a key"))))
+	if _, err := client.GetRepo(context.Background(), "o", "r"); err == nil || !strings.Contains(err.Error(), "PEM") {
+		t.Errorf("GetRepo error = %v, want the key error", err)
+	}
+	if len(srv.seen) != 0 {
+		t.Errorf("sent %d requests without credentials", len(srv.seen))
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// verifyJWT checks an RS256 JWT against key and returns its issuer.
+func verifyJWT(key *rsa.PublicKey, jwt string) (string, error) {
+	parts := strings.Split(jwt, ".")
+	if len(parts) != 3 {
+		return "", errBadJWT
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", err
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], sig); err != nil {
+		return "", err
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", err
+	}
+	var claims struct {
+		Iss      string `json:"iss"`
+		Iat, Exp int64
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return "", err
+	}
+	if claims.Exp <= time.Now().Unix() || claims.Iat > time.Now().Unix() {
+		return "", errBadJWT
+	}
+	return claims.Iss, nil
+}
+
+var errBadJWT = errors.New("malformed JWT")
@@ -0,0 +1,50 @@
+package github
+
+import (
+	"context"
+)
+
+// Branch is a branch of a repository. Whether it is protected is public;
+// how is not, see GetBranchProtection.
+type Branch struct {
+	Name      string `json:"name"`
+	Protected bool   `json:"protected"`
+}
+
+// BranchProtection are the rules protecting a branch. Rules that are off
+// are nil.
+type BranchProtection struct {
+	RequiredReviews *struct {
+		Count int `json:"required_approving_review_count"`
+	} `json:"required_pull_request_reviews"`
+	RequiredStatusChecks *struct {
+		Contexts []string `json:"contexts"`
+	} `json:"required_status_checks"`
+	EnforceAdmins *struct {
+		Enabled bool `json:"enabled"`
+	} `json:"enforce_admins"`
+	AllowForcePushes *struct {
+		Enabled bool `json:"enabled"`
+	} `json:"allow_force_pushes"`
+}
+
+// GetBranch fetches a branch of a repository.
+func (c *Client) GetBranch(ctx context.Context, owner, repo, branch string) (*Branch, error) {
+	var b Branch
+	if err := c.get(ctx, c.endpoint("repos/%s/%s/branches/%s", owner, repo, branch), &b); err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// GetBranchProtection fetches the protection rules of a branch. Reading
+// them needs admin rights on the repository: without, the request fails,
+// usually with ErrNotFound, which is also the error for an unprotected
+// branch.
+func (c *Client) GetBranchProtection(ctx context.Context, owner, repo, branch string) (*BranchProtection, error) {
+	var p BranchProtection
+	if err := c.get(ctx, c.endpoint("repos/%s/%s/branches/%s/protection", owner, repo, branch), &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
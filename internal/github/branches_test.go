@@ -0,0 +1,36 @@
+package github_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+	"github.com/agnivo988/Repo-lyzer/internal/github/githubtest"
+)
+
+func TestGetBranch(t *testing.T) {
+	client := githubtest.NewServer(t).Client()
+	ctx := context.Background()
+
+	branch, err := client.GetBranch(ctx, "octo-org", "busy", "main")
+	if err != nil {
+		t.Fatalf("GetBranch: %v", err)
+	}
+	if branch.Name != "main" || !branch.Protected {
+		t.Errorf("branch = %+v", branch)
+	}
+
+	p, err := client.GetBranchProtection(ctx, "octo-org", "busy", "main")
+	if err != nil {
+		t.Fatalf("GetBranchProtection: %v", err)
+	}
+	if p.RequiredReviews == nil || p.RequiredReviews.Count != 1 || len(p.RequiredStatusChecks.Contexts) != 2 || p.EnforceAdmins.Enabled {
+		t.Errorf("protection = %+v", p)
+	}
+
+	// Unprotected branches have no rules to read.
+	if _, err := client.GetBranchProtection(ctx, "octo-org", "solo", "main"); !errors.Is(err, github.ErrNotFound) {
+		t.Errorf("solo: error = %v, want ErrNotFound", err)
+	}
+}
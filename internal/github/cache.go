@@ -0,0 +1,209 @@
+package github
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// DefaultCacheTTL is how long a cached response is served without asking
+// GitHub. Older entries are revalidated with If-None-Match, which does not
+// count against the rate limit when the data is unchanged.
+const DefaultCacheTTL = 5 * time.Minute
+
+// cachedHeaders are the response headers worth replaying from the cache.
+var cachedHeaders = []string{"Content-Type", "ETag", "Last-Modified", "Link"}
+
+// DefaultCacheDir returns the directory responses are cached in,
+// $XDG_CACHE_HOME/repo-lyzer/http or its platform equivalent.
+func DefaultCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "repo-lyzer", "http"), nil
+}
+
+// ClearCache deletes every cached response under dir, and the temporary
+// files of writes that were cut short, and reports how many responses were
+// removed.
+func ClearCache(dir string) (int, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, e := range entries {
+		ext := filepath.Ext(e.Name())
+		if e.IsDir() || (ext != ".json" && ext != ".tmp") {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, e.Name())); err != nil {
+			return removed, err
+		}
+		if ext == ".json" {
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+// cacheEntry is the on-disk form of a cached response.
+type cacheEntry struct {
+	URL      string      `json:"url"`
+	StoredAt time.Time   `json:"stored_at"`
+	Header   http.Header `json:"header"`
+	Body     []byte      `json:"body"`
+}
+
+// cacheTransport is an http.RoundTripper serving GET requests from an
+// on-disk cache. Entries younger than ttl are returned as is; older ones are
+// revalidated with their ETag or Last-Modified validator.
+type cacheTransport struct {
+	base     http.RoundTripper
+	dir      string
+	ttl      time.Duration
+	identity string // credentials of requests without an Authorization header
+}
+
+func (t *cacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet || strings.HasSuffix(req.URL.Path, "/rate_limit") {
+		return t.base.RoundTrip(req)
+	}
+
+	key := t.key(req)
+	entry, cached := t.load(key)
+	if cached && time.Since(entry.StoredAt) < t.ttl {
+		return entry.response(req), nil
+	}
+
+	if cached {
+		req = req.Clone(req.Context())
+		if etag := entry.Header.Get("ETag"); etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		if modified := entry.Header.Get("Last-Modified"); modified != "" {
+			req.Header.Set("If-Modified-Since", modified)
+		}
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cached:
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		entry.StoredAt = time.Now()
+		t.store(key, entry)
+		return entry.response(req), nil
+
+	case resp.StatusCode == http.StatusOK:
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+
+		entry = &cacheEntry{
+			URL:      req.URL.String(),
+			StoredAt: time.Now(),
+			Header:   make(http.Header),
+			Body:     body,
+		}
+		for _, h := range cachedHeaders {
+			if v := resp.Header.Get(h); v != "" {
+				entry.Header.Set(h, v)
+			}
+		}
+		t.store(key, entry)
+	}
+	return resp, nil
+}
+
+// key identifies a response by URL and by the credentials it was fetched
+// with, so a private repository fetched with one token is never served to
+// another. Requests authenticated further down by the token pool are keyed
+// by the pool's identity. Only a hash of the tokens ends up on disk.
+func (t *cacheTransport) key(req *http.Request) string {
+	identity := req.Header.Get("Authorization")
+	if identity == "" {
+		identity = t.identity
+	}
+	auth := sha256.Sum256([]byte(identity))
+	sum := sha256.Sum256([]byte(req.URL.String() + "\x00" + hex.EncodeToString(auth[:])))
+	return hex.EncodeToString(sum[:])
+}
+
+func (t *cacheTransport) path(key string) string {
+	return filepath.Join(t.dir, key+".json")
+}
+
+func (t *cacheTransport) load(key string) (*cacheEntry, bool) {
+	data, err := os.ReadFile(t.path(key))
+	if err != nil {
+		return nil, false
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, false
+	}
+	return &entry, true
+}
+
+// store writes entry atomically. The cache is best effort: failures only
+// cost a future request, so they are ignored.
+func (t *cacheTransport) store(key string, entry *cacheEntry) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(t.dir, 0o700); err != nil {
+		return
+	}
+	tmp, err := os.CreateTemp(t.dir, key+".*.tmp")
+	if err != nil {
+		return
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return
+	}
+	if err := os.Rename(tmp.Name(), t.path(key)); err != nil {
+		os.Remove(tmp.Name())
+	}
+}
+
+func (e *cacheEntry) response(req *http.Request) *http.Response {
+	header := e.Header.Clone()
+	header.Set("X-Repo-Lyzer-Cache", "hit")
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
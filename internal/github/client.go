@@ -1,48 +1,280 @@
 package github
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"os"
+	"strings"
+	"time"
+)
+
+// DefaultRequestTimeout bounds a single HTTP round trip, including reading
+// the response body.
+const DefaultRequestTimeout = 30 * time.Second
+
+// Endpoints and identification used unless overridden by options or the
+// GITHUB_API_URL environment variable.
+const (
+	DefaultBaseURL   = "https://api.github.com/"
+	DefaultUploadURL = "https://uploads.github.com/"
+	DefaultUserAgent = "Repo-lyzer"
 )
 
 type Client struct {
-	http *http.Client
-	token string
+	http   *http.Client
+	limits *rateTracker
+
+	resolvers []Resolver
+	pooled    bool       // every resolver contributes, rather than the first
+	pool      *tokenPool // empty when unauthenticated
+	authErr   error
+
+	baseURL   string
+	uploadURL string
+	userAgent string
+
+	requestTimeout   time.Duration
+	maxRetries       int
+	maxRateLimitWait time.Duration
+	cacheDir         string // "" disables the response cache
+	cacheTTL         time.Duration
+}
+
+// Option configures a Client built by NewClient.
+type Option func(*Client)
+
+// WithBaseURL points the client at another REST API root, such as
+// https://github.example.com/api/v3/ for GitHub Enterprise Server or an
+// httptest server. Unless set explicitly, the upload URL follows along.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = withTrailingSlash(baseURL)
+		c.uploadURL = uploadURLFor(c.baseURL)
+	}
+}
+
+// WithCredentials replaces the chain of places NewClient looks for a token
+// (DefaultResolvers). The first resolver that finds credentials wins; with
+// none the client is unauthenticated.
+func WithCredentials(resolvers ...Resolver) Option {
+	return func(c *Client) {
+		c.resolvers = resolvers
+	}
+}
+
+// WithTokenPool authenticates with every credential the resolvers find,
+// instead of only the first. Each request is sent with the token that has
+// the most quota left, so scans that outlast one token's hourly quota carry
+// on with the next.
+func WithTokenPool(resolvers ...Resolver) Option {
+	return func(c *Client) {
+		c.resolvers = resolvers
+		c.pooled = true
+	}
+}
+
+// WithUploadURL sets the root used for upload endpoints.
+func WithUploadURL(uploadURL string) Option {
+	return func(c *Client) {
+		c.uploadURL = withTrailingSlash(uploadURL)
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithRequestTimeout sets the per-request timeout. A value <= 0 disables it,
+// leaving only the deadline of the caller's context.
+func WithRequestTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		c.requestTimeout = d
+	}
+}
+
+// WithMaxRetries sets how often a rate-limited or transiently failing
+// request is retried. 0 disables retries.
+func WithMaxRetries(n int) Option {
+	return func(c *Client) {
+		c.maxRetries = n
+	}
+}
+
+// WithMaxRateLimitWait sets the longest the client sleeps waiting for a rate
+// limit to reset before giving up with ErrRateLimited.
+func WithMaxRateLimitWait(d time.Duration) Option {
+	return func(c *Client) {
+		c.maxRateLimitWait = d
+	}
+}
+
+// WithCache enables the on-disk response cache in dir, serving entries
+// younger than ttl without a request. An empty dir selects DefaultCacheDir.
+func WithCache(dir string, ttl time.Duration) Option {
+	return func(c *Client) {
+		if dir == "" {
+			dir, _ = DefaultCacheDir()
+		}
+		c.cacheDir = dir
+		c.cacheTTL = ttl
+	}
+}
+
+func NewClient(opts ...Option) *Client {
+	c := &Client{
+		limits:           newRateTracker(),
+		resolvers:        DefaultResolvers(),
+		baseURL:          DefaultBaseURL,
+		uploadURL:        DefaultUploadURL,
+		userAgent:        DefaultUserAgent,
+		requestTimeout:   DefaultRequestTimeout,
+		maxRetries:       DefaultMaxRetries,
+		maxRateLimitWait: DefaultMaxRateLimitWait,
+	}
+	if env := os.Getenv("GITHUB_API_URL"); env != "" {
+		WithBaseURL(env)(c)
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	// A configured but unusable credential source is reported by every
+	// request rather than silently falling back to anonymous access.
+	c.pool = &tokenPool{limits: c.limits}
+	c.pool.members, c.authErr = resolveCredentials(c, c.resolvers, c.pooled)
+
+	var transport http.RoundTripper = &rateLimitTransport{
+		base:       http.DefaultTransport,
+		limits:     c.limits,
+		pool:       c.pool,
+		timeout:    c.requestTimeout,
+		maxRetries: c.maxRetries,
+		maxWait:    c.maxRateLimitWait,
+	}
+	if c.cacheDir != "" {
+		transport = &cacheTransport{base: transport, dir: c.cacheDir, ttl: c.cacheTTL, identity: c.pool.identity()}
+	}
+	c.http = &http.Client{Transport: transport}
+	return c
+}
+
+// Authenticated reports whether the client sends credentials.
+func (c *Client) Authenticated() bool {
+	return c.pool.size() > 0
+}
+
+// AuthSource describes where the client's tokens come from, or returns ""
+// when the client is unauthenticated.
+func (c *Client) AuthSource() string {
+	var sources []string
+	for _, member := range c.pool.members {
+		sources = append(sources, member.Source())
+	}
+	return strings.Join(sources, ", ")
 }
 
-func NewClient() *Client {
-	return &Client{
-		http:  &http.Client{},
-		token: os.Getenv("GITHUB_TOKEN"),
+// BaseURL returns the REST API root the client talks to.
+func (c *Client) BaseURL() string {
+	return c.baseURL
+}
+
+// UploadURL returns the root used for upload endpoints.
+func (c *Client) UploadURL() string {
+	return c.uploadURL
+}
+
+// endpoint resolves a path relative to the API root, e.g.
+// c.endpoint("repos/%s/%s", owner, repo).
+func (c *Client) endpoint(format string, args ...interface{}) string {
+	return c.baseURL + fmt.Sprintf(format, args...)
+}
+
+func (c *Client) get(ctx context.Context, url string, target interface{}) error {
+	_, err := c.getPage(ctx, url, target)
+	return err
+}
+
+// getPage decodes a single response into target and returns the URL of the
+// next page advertised in the Link header, or "" on the last page.
+func (c *Client) getPage(ctx context.Context, url string, target interface{}) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := c.do(req, target)
+	if err != nil {
+		return "", err
 	}
+	return nextPageURL(resp.Header.Get("Link")), nil
 }
 
-func (c *Client) get(url string, target interface{}) error {
-	req, err := http.NewRequest("GET", url, nil)
+// post sends body as JSON to url and decodes the response into target.
+func (c *Client) post(ctx context.Context, url string, body, target interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(data))
 	if err != nil {
 		return err
 	}
+	req.Header.Set("Content-Type", "application/json")
 
+	_, err = c.do(req, target)
+	return err
+}
+
+// do sends a request and decodes a successful response into target. The
+// transport authenticates it unless it already carries an Authorization
+// header. The returned response has its body closed.
+func (c *Client) do(req *http.Request, target interface{}) (*http.Response, error) {
 	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("User-Agent", c.userAgent)
 
-	if c.token != "" {
-		req.Header.Set("Authorization", "Bearer "+c.token)
+	if c.authErr != nil {
+		return nil, c.authErr
 	}
 
 	resp, err := c.http.Do(req)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf(
-	    "GitHub API error: %s (tip: set GITHUB_TOKEN env variable)",
-	       resp.Status,
-)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, newAPIError(resp)
 	}
 
-	return json.NewDecoder(resp.Body).Decode(target)
+	if err := json.NewDecoder(resp.Body).Decode(target); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func withTrailingSlash(u string) string {
+	if !strings.HasSuffix(u, "/") {
+		return u + "/"
+	}
+	return u
+}
+
+// uploadURLFor derives the upload root matching an API root: GitHub.com
+// keeps uploads on their own host, Enterprise Server serves them from
+// /api/uploads/ next to /api/v3/.
+func uploadURLFor(baseURL string) string {
+	if baseURL == DefaultBaseURL {
+		return DefaultUploadURL
+	}
+	if strings.HasSuffix(baseURL, "/api/v3/") {
+		return strings.TrimSuffix(baseURL, "v3/") + "uploads/"
+	}
+	return baseURL
 }
@@ -0,0 +1,103 @@
+package github_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+	"github.com/agnivo988/Repo-lyzer/internal/github/githubtest"
+)
+
+func TestGetRepo(t *testing.T) {
+	client := githubtest.NewServer(t).Client()
+
+	repo, err := client.GetRepo(context.Background(), "octo-org", "busy")
+	if err != nil {
+		t.Fatalf("GetRepo: %v", err)
+	}
+	if repo.FullName != "octo-org/busy" || repo.Stars != 1280 || repo.DefaultBranch != "main" {
+		t.Errorf("GetRepo = %+v", repo)
+	}
+}
+
+func TestGetRepoNotFound(t *testing.T) {
+	client := githubtest.NewServer(t).Client()
+
+	_, err := client.GetRepo(context.Background(), "octo-org", "missing")
+	if !errors.Is(err, github.ErrNotFound) {
+		t.Fatalf("GetRepo error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestListEndpoints(t *testing.T) {
+	ctx := context.Background()
+	client := githubtest.NewServer(t).Client()
+
+	tests := []struct {
+		name  string
+		fetch func() (int, error)
+		want  int
+	}{
+		{"commits follow pagination", func() (int, error) {
+			commits, err := client.GetCommits(ctx, "octo-org", "busy", github.LastDays(365))
+			return len(commits), err
+		}, 150},
+		{"contributors", func() (int, error) {
+			contributors, err := client.GetContributors(ctx, "octo-org", "busy")
+			return len(contributors), err
+		}, 6},
+		{"issues without pull requests", func() (int, error) {
+			issues, err := client.GetIssues(ctx, "octo-org", "busy", github.IssueOptions{})
+			return len(issues), err
+		}, 24},
+		{"releases", func() (int, error) {
+			releases, err := client.GetReleases(ctx, "octo-org", "busy")
+			return len(releases), err
+		}, 7},
+		{"tags", func() (int, error) {
+			tags, err := client.GetTags(ctx, "octo-org", "busy")
+			return len(tags), err
+		}, 7},
+		{"languages", func() (int, error) {
+			langs, err := client.GetLanguages(ctx, "octo-org", "busy")
+			return len(langs), err
+		}, 3},
+		{"file tree", func() (int, error) {
+			tree, err := client.GetFileTree(ctx, "octo-org", "busy", "main")
+			return len(tree), err
+		}, 21},
+		{"empty list", func() (int, error) {
+			issues, err := client.GetIssues(ctx, "octo-org", "solo", github.IssueOptions{})
+			return len(issues), err
+		}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.fetch()
+			if err != nil {
+				t.Fatalf("fetch: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %d items, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetRateLimitUsesTrackedHeaders(t *testing.T) {
+	ctx := context.Background()
+	client := githubtest.NewServer(t).Client()
+
+	if _, err := client.GetRepo(ctx, "octo-org", "busy"); err != nil {
+		t.Fatalf("GetRepo: %v", err)
+	}
+	rl, err := client.GetRateLimit(ctx)
+	if err != nil {
+		t.Fatalf("GetRateLimit: %v", err)
+	}
+	if rl.Resources.Core.Limit != 5000 || rl.Resources.Core.Remaining != 4990 {
+		t.Errorf("core quota = %+v, want 4990/5000", rl.Resources.Core)
+	}
+}
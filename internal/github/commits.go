@@ -1,22 +1,166 @@
 package github
 
-import "time"
+import (
+	"context"
+	"errors"
+	"net/url"
+	"regexp"
+	"strconv"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// statsConcurrency caps the per-item requests made at once when commit
+// stats or pull request details are asked for.
+const statsConcurrency = 4
 
 type Commit struct {
 	SHA    string `json:"sha"`
 	Commit struct {
-		Author struct {
-			Date time.Time `json:"date"`
-		} `json:"author"`
+		Author    CommitIdentity `json:"author"`
+		Committer CommitIdentity `json:"committer"`
+		Message   string         `json:"message"`
 	} `json:"commit"`
+	// Author and Committer are the GitHub accounts the commit is attributed
+	// to, or nil when the git identity isn't linked to one.
+	Author    *User       `json:"author"`
+	Committer *User       `json:"committer"`
+	Parents   []CommitRef `json:"parents"`
+
+	// Stats and Files are only filled in for commits whose stats were asked
+	// for with CommitOptions.Stats. The GraphQL backend fills in Stats for
+	// every commit but never Files.
+	Stats *CommitStats `json:"stats,omitempty"`
+	Files []CommitFile `json:"files,omitempty"`
+}
+
+// CommitIdentity is a git author or committer.
+type CommitIdentity struct {
+	Name  string    `json:"name"`
+	Email string    `json:"email"`
+	Date  time.Time `json:"date"`
+}
+
+// CommitRef points at another commit.
+type CommitRef struct {
+	SHA string `json:"sha"`
+}
+
+// CommitStats counts the lines a commit changed.
+type CommitStats struct {
+	Additions int `json:"additions"`
+	Deletions int `json:"deletions"`
+	Total     int `json:"total"`
+}
+
+// CommitFile is a file changed by a commit.
+type CommitFile struct {
+	Filename         string `json:"filename"`
+	PreviousFilename string `json:"previous_filename,omitempty"`
+	Status           string `json:"status"` // added, removed, modified, renamed, ...
+	Additions        int    `json:"additions"`
+	Deletions        int    `json:"deletions"`
+	Changes          int    `json:"changes"`
+}
+
+// User is the short form of a GitHub account embedded in other objects.
+type User struct {
+	Login string `json:"login"`
+	Type  string `json:"type"`
+}
+
+// AuthorLogin returns the login of the commit's author, or "" when the
+// author isn't linked to a GitHub account.
+func (c *Commit) AuthorLogin() string {
+	if c.Author == nil {
+		return ""
+	}
+	return c.Author.Login
+}
+
+// IsMerge reports whether the commit has more than one parent.
+func (c *Commit) IsMerge() bool {
+	return len(c.Parents) > 1
+}
+
+var coAuthorTrailer = regexp.MustCompile(`(?im)^co-authored-by:\s*(.*?)\s*<([^>]+)>\s*$`)
+
+// CoAuthors returns the people credited in Co-authored-by trailers of the
+// commit message. Their Date is the author date of the commit.
+func (c *Commit) CoAuthors() []CommitIdentity {
+	var coAuthors []CommitIdentity
+	for _, m := range coAuthorTrailer.FindAllStringSubmatch(c.Commit.Message, -1) {
+		coAuthors = append(coAuthors, CommitIdentity{Name: m[1], Email: m[2], Date: c.Commit.Author.Date})
+	}
+	return coAuthors
+}
+
+// CommitOptions selects the commits GetCommits returns. The zero value lists
+// the whole history of the default branch.
+type CommitOptions struct {
+	Since, Until time.Time // zero for no bound
+
+	SHA    string // branch, tag or commit to list history from
+	Path   string // only commits touching this file or directory
+	Author string // GitHub login or email address
+
+	// Stats fetches line counts and changed files for up to this many of
+	// the newest commits, one request per commit. 0 fetches none.
+	Stats int
 }
 
+// LastDays lists the history of the last days days.
+func LastDays(days int) CommitOptions {
+	return CommitOptions{Since: time.Now().UTC().AddDate(0, 0, -days)}
+}
 
-func (c *Client) GetCommits(owner, repo string, days int) ([]Commit, error) {
-	var commits []Commit
-	since := time.Now().AddDate(0, 0, -days).Format(time.RFC3339)
+// GetCommits fetches the commits selected by opts, following pagination up
+// to commitPageCap pages. A failure to fetch stats comes back as a
+// *StatsError, with the commits.
+func (c *Client) GetCommits(ctx context.Context, owner, repo string, opts CommitOptions) ([]Commit, error) {
+	query := url.Values{"per_page": {strconv.Itoa(perPage)}}
+	if !opts.Since.IsZero() {
+		query.Set("since", opts.Since.UTC().Format(time.RFC3339))
+	}
+	if !opts.Until.IsZero() {
+		query.Set("until", opts.Until.UTC().Format(time.RFC3339))
+	}
+	for key, value := range map[string]string{"sha": opts.SHA, "path": opts.Path, "author": opts.Author} {
+		if value != "" {
+			query.Set(key, value)
+		}
+	}
 
-	url := "https://api.github.com/repos/" + owner + "/" + repo + "/commits?since=" + since
-	err := c.get(url, &commits)
+	url := c.endpoint("repos/%s/%s/commits?%s", owner, repo, query.Encode())
+	commits, err := getAll[Commit](ctx, c, url, commitPageCap)
+	if opts.Stats > 0 && len(commits) > 0 {
+		if statsErr := c.addCommitStats(ctx, owner, repo, commits, opts.Stats); statsErr != nil {
+			err = errors.Join(err, &StatsError{Err: statsErr})
+		}
+	}
 	return commits, err
 }
+
+// addCommitStats fills in Stats and Files of the first max commits. Commits
+// whose details could not be fetched are left without them.
+func (c *Client) addCommitStats(ctx context.Context, owner, repo string, commits []Commit, max int) error {
+	if max > len(commits) {
+		max = len(commits)
+	}
+
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(statsConcurrency)
+	for i := range commits[:max] {
+		commit := &commits[i]
+		g.Go(func() error {
+			var detail Commit
+			if err := c.get(ctx, c.endpoint("repos/%s/%s/commits/%s", owner, repo, commit.SHA), &detail); err != nil {
+				return err
+			}
+			commit.Stats, commit.Files = detail.Stats, detail.Files
+			return nil
+		})
+	}
+	return g.Wait()
+}
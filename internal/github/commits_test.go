@@ -0,0 +1,133 @@
+package github_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+	"github.com/agnivo988/Repo-lyzer/internal/github/githubtest"
+)
+
+func TestGetCommitsFilters(t *testing.T) {
+	since := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2026, 6, 30, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		opts github.CommitOptions
+		want url.Values
+	}{
+		{"default branch history", github.CommitOptions{}, url.Values{"per_page": {"100"}}},
+		{"window", github.CommitOptions{Since: since, Until: until}, url.Values{
+			"per_page": {"100"},
+			"since":    {"2026-01-01T00:00:00Z"},
+			"until":    {"2026-06-30T12:00:00Z"},
+		}},
+		{"ref, path and author", github.CommitOptions{SHA: "release/1.x", Path: "cmd/main.go", Author: "alice"}, url.Values{
+			"per_page": {"100"},
+			"sha":      {"release/1.x"},
+			"path":     {"cmd/main.go"},
+			"author":   {"alice"},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got url.Values
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.URL.Query()
+				w.Write([]byte(`[]`))
+			}))
+			defer srv.Close()
+
+			client := github.NewClient(github.WithBaseURL(srv.URL), github.WithCredentials())
+			if _, err := client.GetCommits(context.Background(), "o", "r", tt.opts); err != nil {
+				t.Fatalf("GetCommits: %v", err)
+			}
+			if got.Encode() != tt.want.Encode() {
+				t.Errorf("query = %s, want %s", got.Encode(), tt.want.Encode())
+			}
+		})
+	}
+}
+
+func TestGetCommitsStats(t *testing.T) {
+	client := githubtest.NewServer(t).Client()
+
+	commits, err := client.GetCommits(context.Background(), "octo-org", "solo", github.CommitOptions{Stats: 2})
+	if err != nil {
+		t.Fatalf("GetCommits: %v", err)
+	}
+	if len(commits) != 3 {
+		t.Fatalf("got %d commits, want 3", len(commits))
+	}
+	for i, c := range commits {
+		if wantStats := i < 2; (c.Stats != nil) != wantStats || (len(c.Files) > 0) != wantStats {
+			t.Errorf("commit %d: stats %+v, files %+v", i, c.Stats, c.Files)
+		}
+	}
+	if got := commits[1]; got.Stats.Additions != 13 || got.Files[0].Filename != "main.py" {
+		t.Errorf("commit 1: stats %+v, files %+v", got.Stats, got.Files)
+	}
+	if c := commits[0]; c.AuthorLogin() != "sam" || c.Committer.Login != "sam" || c.Commit.Message != "Change 0" {
+		t.Errorf("commit 0 identity = %+v", c)
+	}
+
+	// Only the newest 100 of busy's commits have details: the others are
+	// listed all the same, and the error says their stats are missing.
+	commits, err = client.GetCommits(context.Background(), "octo-org", "busy", github.CommitOptions{Stats: 120})
+	var statsErr *github.StatsError
+	if !errors.As(err, &statsErr) || !errors.Is(err, github.ErrNotFound) || len(commits) != 150 {
+		t.Errorf("got %d commits, error %v; want 150 and a StatsError", len(commits), err)
+	}
+}
+
+func TestCommitHelpers(t *testing.T) {
+	tests := []struct {
+		name      string
+		message   string
+		parents   int
+		author    *github.User
+		login     string
+		merge     bool
+		coAuthors []string
+	}{
+		{"plain", "Fix the parser", 1, &github.User{Login: "alice"}, "alice", false, nil},
+		{"unlinked merge", "Merge branch 'main'", 2, nil, "", true, nil},
+		{"pair programmed", "Add cache\n\nCo-authored-by: Bob Smith <bob@example.com>\nco-authored-by:carol <carol@example.com>\n",
+			1, &github.User{Login: "alice"}, "alice", false, []string{"Bob Smith <bob@example.com>", "carol <carol@example.com>"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var c github.Commit
+			c.Commit.Message = tt.message
+			c.Parents = make([]github.CommitRef, tt.parents)
+			c.Author = tt.author
+
+			if got := c.AuthorLogin(); got != tt.login {
+				t.Errorf("AuthorLogin() = %q, want %q", got, tt.login)
+			}
+			if got := c.IsMerge(); got != tt.merge {
+				t.Errorf("IsMerge() = %v, want %v", got, tt.merge)
+			}
+			var got []string
+			for _, co := range c.CoAuthors() {
+				got = append(got, co.Name+" <"+co.Email+">")
+			}
+			if len(got) != len(tt.coAuthors) {
+				t.Fatalf("CoAuthors() = %v, want %v", got, tt.coAuthors)
+			}
+			for i := range got {
+				if got[i] != tt.coAuthors[i] {
+					t.Errorf("CoAuthors() = %v, want %v", got, tt.coAuthors)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package github
+
+import (
+	"context"
+)
+
+// CommunityProfile is GitHub's community profile of a repository: which of
+// the recommended community health files it has. GitHub also counts files
+// inherited from the owner's .github repository, which the file tree of the
+// repository itself doesn't show.
+type CommunityProfile struct {
+	HealthPercentage int `json:"health_percentage"`
+	Files            struct {
+		CodeOfConduct       *CommunityFile `json:"code_of_conduct_file"`
+		Contributing        *CommunityFile `json:"contributing"`
+		IssueTemplate       *CommunityFile `json:"issue_template"`
+		PullRequestTemplate *CommunityFile `json:"pull_request_template"`
+		License             *CommunityFile `json:"license"`
+		Readme              *CommunityFile `json:"readme"`
+	} `json:"files"`
+}
+
+// CommunityFile is a file listed in a community profile; files that are
+// missing are nil.
+type CommunityFile struct {
+	URL     string `json:"url"`
+	HTMLURL string `json:"html_url"`
+}
+
+// GetCommunityProfile fetches the community profile of a repository. GitHub
+// only has profiles for public repositories; for others it is ErrNotFound.
+func (c *Client) GetCommunityProfile(ctx context.Context, owner, repo string) (*CommunityProfile, error) {
+	var profile CommunityProfile
+	if err := c.get(ctx, c.endpoint("repos/%s/%s/community/profile", owner, repo), &profile); err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
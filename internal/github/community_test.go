@@ -0,0 +1,28 @@
+package github_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+	"github.com/agnivo988/Repo-lyzer/internal/github/githubtest"
+)
+
+func TestGetCommunityProfile(t *testing.T) {
+	client := githubtest.NewServer(t).Client()
+	ctx := context.Background()
+
+	profile, err := client.GetCommunityProfile(ctx, "octo-org", "busy")
+	if err != nil {
+		t.Fatalf("GetCommunityProfile: %v", err)
+	}
+	if profile.HealthPercentage != 85 || profile.Files.CodeOfConduct == nil || profile.Files.License == nil {
+		t.Errorf("profile = %+v", profile)
+	}
+
+	// Private repositories have no profile.
+	if _, err := client.GetCommunityProfile(ctx, "octo-org", "locked"); !errors.Is(err, github.ErrNotFound) {
+		t.Errorf("locked: error = %v, want ErrNotFound", err)
+	}
+}
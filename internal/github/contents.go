@@ -0,0 +1,71 @@
+package github
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// fileContent is a file as the contents API returns it.
+type fileContent struct {
+	Path     string `json:"path"`
+	Content  string `json:"content"`
+	Encoding string `json:"encoding"`
+}
+
+func (f *fileContent) decode() ([]byte, error) {
+	if f.Encoding != "base64" {
+		return []byte(f.Content), nil
+	}
+	data, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(f.Content, "\n", ""))
+	if err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", f.Path, err)
+	}
+	return data, nil
+}
+
+// LicenseFile is the license file GitHub found in a repository.
+type LicenseFile struct {
+	Path    string
+	Content []byte
+	// License is what GitHub detected in the file.
+	License *License
+}
+
+// GetLicenseFile fetches the license file of a repository. It is ErrNotFound
+// when the repository has none.
+func (c *Client) GetLicenseFile(ctx context.Context, owner, repo string) (*LicenseFile, error) {
+	var resp struct {
+		fileContent
+		License *License `json:"license"`
+	}
+	if err := c.get(ctx, c.endpoint("repos/%s/%s/license", owner, repo), &resp); err != nil {
+		return nil, err
+	}
+	content, err := resp.decode()
+	if err != nil {
+		return nil, err
+	}
+	return &LicenseFile{Path: resp.Path, Content: content, License: resp.License}, nil
+}
+
+// GetFileContent fetches the content of the file at path on ref, or on the
+// default branch when ref is empty.
+func (c *Client) GetFileContent(ctx context.Context, owner, repo, path, ref string) ([]byte, error) {
+	escaped := strings.Split(path, "/")
+	for i, part := range escaped {
+		escaped[i] = url.PathEscape(part)
+	}
+	u := c.endpoint("repos/%s/%s/contents/%s", owner, repo, strings.Join(escaped, "/"))
+	if ref != "" {
+		u += "?ref=" + url.QueryEscape(ref)
+	}
+
+	var f fileContent
+	if err := c.get(ctx, u, &f); err != nil {
+		return nil, err
+	}
+	return f.decode()
+}
@@ -0,0 +1,43 @@
+package github_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+	"github.com/agnivo988/Repo-lyzer/internal/github/githubtest"
+)
+
+func TestGetLicenseFile(t *testing.T) {
+	client := githubtest.NewServer(t).Client()
+	ctx := context.Background()
+
+	file, err := client.GetLicenseFile(ctx, "octo-org", "busy")
+	if err != nil {
+		t.Fatalf("GetLicenseFile: %v", err)
+	}
+	if file.Path != "LICENSE" || file.License == nil || file.License.SPDXID != "MIT" {
+		t.Errorf("file = %s with %+v", file.Path, file.License)
+	}
+	if !bytes.HasPrefix(file.Content, []byte("MIT License\n\nCopyright (c) 2015 Octo Org")) {
+		t.Errorf("content = %.60q", file.Content)
+	}
+
+	if _, err := client.GetLicenseFile(ctx, "octo-org", "solo"); !errors.Is(err, github.ErrNotFound) {
+		t.Errorf("solo: error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestGetFileContent(t *testing.T) {
+	client := githubtest.NewServer(t).Client()
+
+	content, err := client.GetFileContent(context.Background(), "octo-org", "busy", "internal/server/server.go", "main")
+	if err != nil {
+		t.Fatalf("GetFileContent: %v", err)
+	}
+	if !bytes.Contains(content, []byte("SPDX-License-Identifier: GPL-3.0-only\n\npackage server\n")) {
+		t.Errorf("content = %q", content)
+	}
+}
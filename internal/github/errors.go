@@ -0,0 +1,106 @@
+package github
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Sentinel errors for the API failures callers want to tell apart. Errors
+// returned by Client methods wrap one of these when applicable, so test with
+// errors.Is.
+var (
+	ErrNotFound     = errors.New("github: not found")
+	ErrUnauthorized = errors.New("github: unauthorized")
+	ErrRateLimited  = errors.New("github: rate limit exceeded")
+
+	// ErrTruncated is returned along with the data that was fetched when
+	// a list stopped short of its end, because of a page cap or because
+	// GitHub truncated the response itself.
+	ErrTruncated = errors.New("github: result truncated")
+)
+
+// APIError describes a non-successful GitHub API response.
+type APIError struct {
+	StatusCode int
+	Status     string
+	URL        string
+	Message    string    // "message" field of the error body, if any
+	ResetAt    time.Time // when the exhausted quota resets, for rate limit errors
+
+	kind error
+}
+
+func (e *APIError) Error() string {
+	msg := "GitHub API error: " + e.Status
+	if e.Message != "" && !strings.HasSuffix(e.Status, e.Message) {
+		msg += " (" + e.Message + ")"
+	}
+	return msg
+}
+
+// Unwrap exposes the sentinel error matching the response, if any.
+func (e *APIError) Unwrap() error {
+	return e.kind
+}
+
+// StatsError is returned by GetCommits, joined to any error listing the
+// commits, when the stats of some of them could not be fetched. They are
+// left without Stats and Files.
+type StatsError struct {
+	Err error
+}
+
+func (e *StatsError) Error() string {
+	return "fetching commit stats: " + e.Err.Error()
+}
+
+func (e *StatsError) Unwrap() error {
+	return e.Err
+}
+
+// newAPIError builds an APIError from a failed response, consuming its body.
+func newAPIError(resp *http.Response) *APIError {
+	e := &APIError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		URL:        resp.Request.URL.String(),
+	}
+
+	var body struct {
+		Message string `json:"message"`
+	}
+	if data, err := io.ReadAll(io.LimitReader(resp.Body, 64<<10)); err == nil {
+		if json.Unmarshal(data, &body) == nil {
+			e.Message = body.Message
+		}
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		e.kind = ErrNotFound
+	case resp.StatusCode == http.StatusUnauthorized:
+		e.kind = ErrUnauthorized
+	case isRateLimited(resp, e.Message):
+		e.kind = ErrRateLimited
+		if state, ok := parseRateState(resp.Header); ok && state.Remaining == 0 {
+			e.ResetAt = state.Reset
+		} else if wait, ok := retryAfter(resp.Header); ok {
+			e.ResetAt = time.Now().Add(wait)
+		}
+	}
+	return e
+}
+
+// RateLimitReset returns when the quota behind err resets, if err is a rate
+// limit error that carries that information.
+func RateLimitReset(err error) (time.Time, bool) {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && errors.Is(err, ErrRateLimited) && !apiErr.ResetAt.IsZero() {
+		return apiErr.ResetAt, true
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,85 @@
+package github_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+func TestAPIErrorKinds(t *testing.T) {
+	reset := time.Now().Add(time.Hour).Unix()
+
+	tests := []struct {
+		name    string
+		status  int
+		header  map[string]string
+		message string
+		want    error
+	}{
+		{"not found", http.StatusNotFound, nil, "Not Found", github.ErrNotFound},
+		{"bad credentials", http.StatusUnauthorized, nil, "Bad credentials", github.ErrUnauthorized},
+		{"primary rate limit", http.StatusForbidden, map[string]string{
+			"X-RateLimit-Remaining": "0",
+			"X-RateLimit-Reset":     strconv.FormatInt(reset, 10),
+		}, "API rate limit exceeded", github.ErrRateLimited},
+		{"secondary rate limit", http.StatusForbidden, nil,
+			"You have exceeded a secondary rate limit", github.ErrRateLimited},
+		{"too many requests", http.StatusTooManyRequests, map[string]string{"Retry-After": "3600"},
+			"", github.ErrRateLimited},
+		{"forbidden", http.StatusForbidden, nil, "Resource not accessible by integration", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				for k, v := range tt.header {
+					w.Header().Set(k, v)
+				}
+				w.WriteHeader(tt.status)
+				w.Write([]byte(`{"message":"` + tt.message + `"}`))
+			}))
+			defer srv.Close()
+
+			client := github.NewClient(github.WithBaseURL(srv.URL), github.WithMaxRetries(0))
+			_, err := client.GetRepo(context.Background(), "octo-org", "busy")
+
+			var apiErr *github.APIError
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != tt.status {
+				t.Fatalf("error = %v, want APIError with status %d", err, tt.status)
+			}
+			for _, sentinel := range []error{github.ErrNotFound, github.ErrUnauthorized, github.ErrRateLimited} {
+				if got := errors.Is(err, sentinel); got != (sentinel == tt.want) {
+					t.Errorf("errors.Is(err, %v) = %v", sentinel, got)
+				}
+			}
+		})
+	}
+}
+
+func TestRetriesServerErrors(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`{"full_name":"octo-org/busy"}`))
+	}))
+	defer srv.Close()
+
+	client := github.NewClient(github.WithBaseURL(srv.URL), github.WithMaxRetries(1))
+	repo, err := client.GetRepo(context.Background(), "octo-org", "busy")
+	if err != nil {
+		t.Fatalf("GetRepo: %v", err)
+	}
+	if repo.FullName != "octo-org/busy" || calls != 2 {
+		t.Errorf("got %q after %d calls, want octo-org/busy after 2", repo.FullName, calls)
+	}
+}
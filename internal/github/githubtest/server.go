@@ -0,0 +1,220 @@
+// Package githubtest serves recorded GitHub REST API responses from a local
+// httptest.Server, so the client, the analyzers and the commands can be
+// tested without the network.
+//
+// Fixtures live under testdata/ and mirror the request path: the response to
+// GET /repos/octo-org/busy/commits is testdata/repos/octo-org/busy/commits.json
+// and its second page is commits.page2.json. The query string is otherwise
+// ignored. A missing fixture is answered with 404 Not Found.
+//
+// Setting REPOLYZER_RECORD=1 switches the server to record mode: requests are
+// proxied to https://api.github.com (or $REPOLYZER_RECORD_URL), using
+// GITHUB_TOKEN if set, and every successful response is written to testdata.
+package githubtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+// Server replays (or records) GitHub API fixtures.
+type Server struct {
+	*httptest.Server
+
+	dir      string
+	upstream string // non-empty in record mode
+	t        testing.TB
+}
+
+// NewServer starts a fixture server over the bundled testdata directory. It
+// is shut down when the test ends.
+func NewServer(t testing.TB) *Server {
+	t.Helper()
+	return NewServerAt(t, Dir())
+}
+
+// NewServerAt starts a fixture server over dir.
+func NewServerAt(t testing.TB, dir string) *Server {
+	t.Helper()
+
+	s := &Server{dir: dir, t: t}
+	if os.Getenv("REPOLYZER_RECORD") != "" {
+		s.upstream = strings.TrimSuffix(github.DefaultBaseURL, "/")
+		if u := os.Getenv("REPOLYZER_RECORD_URL"); u != "" {
+			s.upstream = strings.TrimSuffix(u, "/")
+		}
+	}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Dir returns the bundled testdata directory.
+func Dir() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Join(filepath.Dir(file), "testdata")
+}
+
+// Client returns a GitHub client talking to the server, with retries and the
+// response cache disabled so tests see every response as served.
+func (s *Server) Client(opts ...github.Option) *github.Client {
+	opts = append([]github.Option{
+		github.WithBaseURL(s.URL),
+		github.WithMaxRetries(0),
+		github.WithCredentials(),
+	}, opts...)
+	return github.NewClient(opts...)
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	page := 1
+	if p, err := strconv.Atoi(r.URL.Query().Get("page")); err == nil && p > 1 {
+		page = p
+	}
+
+	if s.upstream != "" {
+		s.record(w, r, page)
+		return
+	}
+
+	body, err := os.ReadFile(s.fixturePath(r.URL.Path, page))
+	if err != nil {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	s.setRateLimitHeaders(w)
+	if _, err := os.Stat(s.fixturePath(r.URL.Path, page+1)); err == nil {
+		w.Header().Set("Link", fmt.Sprintf(`<%s>; rel="next"`, s.pageURL(r, page+1)))
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Write(body)
+}
+
+// record proxies r to the upstream API and saves a successful response as
+// the fixture for its path and page.
+func (s *Server) record(w http.ResponseWriter, r *http.Request, page int) {
+	req, err := http.NewRequestWithContext(r.Context(), r.Method, s.upstream+r.URL.RequestURI(), nil)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("User-Agent", github.DefaultUserAgent)
+	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+
+	if resp.StatusCode == http.StatusOK {
+		if err := s.save(r.URL.Path, page, body); err != nil {
+			s.t.Errorf("githubtest: recording %s: %v", r.URL.Path, err)
+		}
+		if next := nextLink(resp.Header.Get("Link")); next != nil {
+			w.Header().Set("Link", fmt.Sprintf(`<%s%s>; rel="next"`, s.URL, next.RequestURI()))
+		}
+	}
+	for _, h := range []string{"Content-Type", "X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset", "X-RateLimit-Resource"} {
+		if v := resp.Header.Get(h); v != "" {
+			w.Header().Set(h, v)
+		}
+	}
+	w.WriteHeader(resp.StatusCode)
+	w.Write(body)
+}
+
+// save writes body as indented JSON so recorded fixtures diff cleanly.
+func (s *Server) save(path string, page int, body []byte) error {
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return err
+	}
+	pretty, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	file := s.fixturePath(path, page)
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(file, append(pretty, '\n'), 0o644)
+}
+
+// setRateLimitHeaders mirrors the core quota of rate_limit.json into the
+// X-RateLimit-* headers, like GitHub does on every response.
+func (s *Server) setRateLimitHeaders(w http.ResponseWriter) {
+	data, err := os.ReadFile(filepath.Join(s.dir, "rate_limit.json"))
+	if err != nil {
+		return
+	}
+	var rl github.RateLimit
+	if json.Unmarshal(data, &rl) != nil {
+		return
+	}
+	core := rl.Resources.Core
+	w.Header().Set("X-RateLimit-Limit", strconv.Itoa(core.Limit))
+	w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(core.Remaining))
+	w.Header().Set("X-RateLimit-Reset", strconv.Itoa(core.Reset))
+	w.Header().Set("X-RateLimit-Resource", "core")
+}
+
+func (s *Server) fixturePath(path string, page int) string {
+	name := strings.Trim(path, "/")
+	if page > 1 {
+		name += ".page" + strconv.Itoa(page)
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(name)+".json")
+}
+
+func (s *Server) pageURL(r *http.Request, page int) string {
+	q := r.URL.Query()
+	q.Set("page", strconv.Itoa(page))
+	return s.URL + r.URL.Path + "?" + q.Encode()
+}
+
+// nextLink extracts the rel="next" URL of a Link header.
+func nextLink(link string) *url.URL {
+	for _, part := range strings.Split(link, ",") {
+		target, params, ok := strings.Cut(part, ";")
+		if !ok || !strings.Contains(params, `rel="next"`) {
+			continue
+		}
+		u, err := url.Parse(strings.Trim(strings.TrimSpace(target), "<>"))
+		if err == nil {
+			return u
+		}
+	}
+	return nil
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"message": message})
+}
@@ -0,0 +1,538 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// snapshotHistoryDays is the commit window fetched together with the
+// repository metadata in the batched snapshot query.
+const snapshotHistoryDays = 365
+
+// GraphQLClient is a RepoSource backed by the GraphQL v4 API. Repository
+// metadata, languages, releases, issue and pull request counts and the first
+// page of commit history arrive in one batched query, which later calls for
+// the same repository reuse. The file tree, file contents, tags, issue lists,
+// branches and community profile are not exposed in a useful shape by
+// GraphQL and come from the embedded REST Client.
+type GraphQLClient struct {
+	*Client
+
+	mu        sync.Mutex
+	snapshots map[string]*snapshotCall
+}
+
+// snapshotCall is a Snapshot query for one repository, shared by the calls
+// that ask for it while it runs. done is closed once s and err are set.
+type snapshotCall struct {
+	done chan struct{}
+	s    *Snapshot
+	err  error
+}
+
+// Snapshot is the result of the batched GraphQL query for a repository.
+type Snapshot struct {
+	Repo      Repo
+	Languages map[string]int
+	Releases  []Release
+	// ReleasesErr is ErrTruncated when Releases stopped at the page cap,
+	// or the error that stopped them short.
+	ReleasesErr error
+
+	// Commits on the default branch in the last snapshotHistoryDays days.
+	Commits []Commit
+	// HistorySince is the start of the window Commits covers.
+	HistorySince time.Time
+	// HistoryErr is ErrTruncated when Commits stopped at the page cap, or
+	// the error of the page that failed after them.
+	HistoryErr error
+}
+
+func NewGraphQLClient(c *Client) *GraphQLClient {
+	return &GraphQLClient{Client: c, snapshots: make(map[string]*snapshotCall)}
+}
+
+// graphqlURL is the GraphQL endpoint matching the REST API root: /graphql
+// on GitHub.com and /api/graphql on Enterprise Server.
+func (g *GraphQLClient) graphqlURL() string {
+	if strings.HasSuffix(g.baseURL, "/api/v3/") {
+		return strings.TrimSuffix(g.baseURL, "v3/") + "graphql"
+	}
+	return g.baseURL + "graphql"
+}
+
+type graphqlError struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+// query runs a GraphQL query and decodes its "data" member into data.
+func (g *GraphQLClient) query(ctx context.Context, query string, vars map[string]interface{}, data interface{}) error {
+	if !g.Authenticated() {
+		return fmt.Errorf("%w: the GraphQL API requires a token", ErrUnauthorized)
+	}
+
+	resp := struct {
+		Data   interface{}    `json:"data"`
+		Errors []graphqlError `json:"errors"`
+	}{Data: data}
+
+	err := g.post(ctx, g.graphqlURL(), map[string]interface{}{
+		"query":     query,
+		"variables": vars,
+	}, &resp)
+	if err != nil {
+		return err
+	}
+
+	if len(resp.Errors) > 0 {
+		e := resp.Errors[0]
+		switch e.Type {
+		case "NOT_FOUND":
+			return fmt.Errorf("%w: %s", ErrNotFound, e.Message)
+		case "RATE_LIMITED":
+			return fmt.Errorf("%w: %s", ErrRateLimited, e.Message)
+		}
+		return errors.New("GitHub GraphQL error: " + e.Message)
+	}
+	return nil
+}
+
+const historyFields = `
+	history(first: 100, since: $since, after: $cursor) {
+		pageInfo { hasNextPage endCursor }
+		nodes {
+			oid
+			message
+			additions
+			deletions
+			author { name email date user { login } }
+			committer { name email date user { login } }
+			parents(first: 10) { nodes { oid } }
+		}
+	}`
+
+var snapshotQuery = `
+query($owner: String!, $name: String!, $since: GitTimestamp!, $cursor: String, $releaseCursor: String) {
+	repository(owner: $owner, name: $name) {
+		name
+		nameWithOwner
+		description
+		stargazerCount
+		forkCount
+		createdAt
+		licenseInfo { key name spdxId }
+		defaultBranchRef {
+			name
+			target { ... on Commit {` + historyFields + ` } }
+		}
+		openIssues: issues(states: OPEN) { totalCount }
+		openPRs: pullRequests(states: OPEN) { totalCount }
+		languages(first: 100, orderBy: {field: SIZE, direction: DESC}) {
+			edges { size node { name } }
+		}
+` + releasesField + `
+	}
+}`
+
+const releasesField = `
+		releases(first: 100, after: $releaseCursor, orderBy: {field: CREATED_AT, direction: DESC}) {
+			pageInfo { hasNextPage endCursor }
+			nodes { tagName name description isDraft isPrerelease createdAt publishedAt }
+		}`
+
+var releasesQuery = `
+query($owner: String!, $name: String!, $releaseCursor: String) {
+	repository(owner: $owner, name: $name) {` + releasesField + `
+	}
+}`
+
+var historyQuery = `
+query($owner: String!, $name: String!, $since: GitTimestamp!, $cursor: String) {
+	repository(owner: $owner, name: $name) {
+		defaultBranchRef {
+			target { ... on Commit {` + historyFields + ` } }
+		}
+	}
+}`
+
+type gqlCount struct {
+	TotalCount int `json:"totalCount"`
+}
+
+type gqlReleases struct {
+	PageInfo struct {
+		HasNextPage bool   `json:"hasNextPage"`
+		EndCursor   string `json:"endCursor"`
+	} `json:"pageInfo"`
+	Nodes []struct {
+		TagName      string    `json:"tagName"`
+		Name         string    `json:"name"`
+		Description  string    `json:"description"`
+		IsDraft      bool      `json:"isDraft"`
+		IsPrerelease bool      `json:"isPrerelease"`
+		CreatedAt    time.Time `json:"createdAt"`
+		PublishedAt  time.Time `json:"publishedAt"`
+	} `json:"nodes"`
+}
+
+type gqlHistory struct {
+	PageInfo struct {
+		HasNextPage bool   `json:"hasNextPage"`
+		EndCursor   string `json:"endCursor"`
+	} `json:"pageInfo"`
+	Nodes []struct {
+		OID       string      `json:"oid"`
+		Message   string      `json:"message"`
+		Additions int         `json:"additions"`
+		Deletions int         `json:"deletions"`
+		Author    gqlGitActor `json:"author"`
+		Committer gqlGitActor `json:"committer"`
+		Parents   struct {
+			Nodes []struct {
+				OID string `json:"oid"`
+			} `json:"nodes"`
+		} `json:"parents"`
+	} `json:"nodes"`
+}
+
+type gqlGitActor struct {
+	Name  string    `json:"name"`
+	Email string    `json:"email"`
+	Date  time.Time `json:"date"`
+	User  *struct {
+		Login string `json:"login"`
+	} `json:"user"`
+}
+
+func (a gqlGitActor) identity() CommitIdentity {
+	return CommitIdentity{Name: a.Name, Email: a.Email, Date: a.Date}
+}
+
+func (a gqlGitActor) user() *User {
+	if a.User == nil {
+		return nil
+	}
+	return &User{Login: a.User.Login}
+}
+
+type gqlBranchRef struct {
+	Name   string `json:"name"`
+	Target struct {
+		History gqlHistory `json:"history"`
+	} `json:"target"`
+}
+
+// Snapshot returns the batched view of owner/repo, querying GitHub only the
+// first time it is asked for. Calls made while that query runs wait for it
+// rather than querying again; a failed query is tried again by the next
+// call.
+func (g *GraphQLClient) Snapshot(ctx context.Context, owner, repo string) (*Snapshot, error) {
+	key := strings.ToLower(owner + "/" + repo)
+
+	g.mu.Lock()
+	call, ok := g.snapshots[key]
+	if !ok {
+		call = &snapshotCall{done: make(chan struct{})}
+		g.snapshots[key] = call
+	}
+	g.mu.Unlock()
+
+	if ok {
+		select {
+		case <-call.done:
+			return call.s, call.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	call.s, call.err = g.snapshot(ctx, owner, repo)
+	if call.err != nil {
+		g.mu.Lock()
+		delete(g.snapshots, key)
+		g.mu.Unlock()
+	}
+	close(call.done)
+	return call.s, call.err
+}
+
+// snapshot runs the batched query for owner/repo and pages the history and
+// releases it started.
+func (g *GraphQLClient) snapshot(ctx context.Context, owner, repo string) (*Snapshot, error) {
+	var data struct {
+		Repository *struct {
+			Name           string    `json:"name"`
+			NameWithOwner  string    `json:"nameWithOwner"`
+			Description    string    `json:"description"`
+			StargazerCount int       `json:"stargazerCount"`
+			ForkCount      int       `json:"forkCount"`
+			CreatedAt      time.Time `json:"createdAt"`
+			LicenseInfo    *struct {
+				Key    string `json:"key"`
+				Name   string `json:"name"`
+				SPDXID string `json:"spdxId"`
+			} `json:"licenseInfo"`
+			DefaultBranchRef *gqlBranchRef `json:"defaultBranchRef"`
+			OpenIssues       gqlCount      `json:"openIssues"`
+			OpenPRs          gqlCount      `json:"openPRs"`
+			Languages        struct {
+				Edges []struct {
+					Size int `json:"size"`
+					Node struct {
+						Name string `json:"name"`
+					} `json:"node"`
+				} `json:"edges"`
+			} `json:"languages"`
+			Releases gqlReleases `json:"releases"`
+		} `json:"repository"`
+	}
+
+	since := time.Now().UTC().AddDate(0, 0, -snapshotHistoryDays)
+	vars := historyVars(owner, repo, since)
+	if err := g.query(ctx, snapshotQuery, vars, &data); err != nil {
+		return nil, err
+	}
+	r := data.Repository
+	if r == nil {
+		return nil, fmt.Errorf("%w: repository %s/%s", ErrNotFound, owner, repo)
+	}
+
+	s := &Snapshot{
+		HistorySince: since,
+		Repo: Repo{
+			Name:        r.Name,
+			FullName:    r.NameWithOwner,
+			Stars:       r.StargazerCount,
+			Forks:       r.ForkCount,
+			OpenIssues:  r.OpenIssues.TotalCount + r.OpenPRs.TotalCount,
+			Description: r.Description,
+			CreatedAt:   r.CreatedAt,
+		},
+		Languages: make(map[string]int),
+	}
+	if l := r.LicenseInfo; l != nil {
+		s.Repo.License = &License{Key: l.Key, Name: l.Name, SPDXID: l.SPDXID}
+	}
+	for _, e := range r.Languages.Edges {
+		s.Languages[e.Node.Name] = e.Size
+	}
+	// A later page of releases failing only leaves them short, as it does
+	// over REST; GetReleases reports it.
+	s.Releases, s.ReleasesErr = g.releases(ctx, owner, repo, &r.Releases)
+
+	if ref := r.DefaultBranchRef; ref != nil {
+		s.Repo.DefaultBranch = ref.Name
+		// Likewise a later page of history failing leaves the commits
+		// partial rather than failing the repository.
+		s.Commits, s.HistoryErr = g.history(ctx, vars, &ref.Target.History)
+	}
+
+	return s, nil
+}
+
+// releases collects the releases whose first page came with the snapshot,
+// up to releasePageCap pages, returning ErrTruncated with the releases if
+// more were left.
+func (g *GraphQLClient) releases(ctx context.Context, owner, repo string, first *gqlReleases) ([]Release, error) {
+	page := *first
+	var releases []Release
+	for pages := 1; ; pages++ {
+		for _, n := range page.Nodes {
+			releases = append(releases, Release{
+				TagName:     n.TagName,
+				Name:        n.Name,
+				Body:        n.Description,
+				Draft:       n.IsDraft,
+				Prerelease:  n.IsPrerelease,
+				CreatedAt:   n.CreatedAt,
+				PublishedAt: n.PublishedAt,
+			})
+		}
+		if !page.PageInfo.HasNextPage {
+			return releases, nil
+		}
+		if pages >= releasePageCap {
+			return releases, fmt.Errorf("%w: stopped after %d pages", ErrTruncated, releasePageCap)
+		}
+
+		var data struct {
+			Repository *struct {
+				Releases gqlReleases `json:"releases"`
+			} `json:"repository"`
+		}
+		vars := map[string]interface{}{"owner": owner, "name": repo, "releaseCursor": page.PageInfo.EndCursor}
+		if err := g.query(ctx, releasesQuery, vars, &data); err != nil {
+			return releases, err
+		}
+		if data.Repository == nil {
+			return releases, nil
+		}
+		page = data.Repository.Releases
+	}
+}
+
+// history collects the default branch history selected by vars, up to
+// commitPageCap pages, returning ErrTruncated with the commits if more were
+// left, and with the error of a page that failed. first is the page
+// already fetched along with the snapshot, or nil to start from scratch.
+func (g *GraphQLClient) history(ctx context.Context, vars map[string]interface{}, first *gqlHistory) ([]Commit, error) {
+	var commits []Commit
+	pages := 0
+	if first != nil {
+		commits = historyCommits(*first)
+		pages = 1
+		if !first.PageInfo.HasNextPage {
+			return commits, nil
+		}
+		vars["cursor"] = first.PageInfo.EndCursor
+	}
+
+	for ; ; pages++ {
+		if pages >= commitPageCap {
+			return commits, fmt.Errorf("%w: stopped after %d pages", ErrTruncated, commitPageCap)
+		}
+		var data struct {
+			Repository *struct {
+				DefaultBranchRef *gqlBranchRef `json:"defaultBranchRef"`
+			} `json:"repository"`
+		}
+		if err := g.query(ctx, historyQuery, vars, &data); err != nil {
+			return commits, err
+		}
+		if data.Repository == nil || data.Repository.DefaultBranchRef == nil {
+			break
+		}
+		page := data.Repository.DefaultBranchRef.Target.History
+		commits = append(commits, historyCommits(page)...)
+		if !page.PageInfo.HasNextPage {
+			break
+		}
+		vars["cursor"] = page.PageInfo.EndCursor
+	}
+	return commits, nil
+}
+
+// historyVars selects the history since since; the zero time selects all
+// of it.
+func historyVars(owner, repo string, since time.Time) map[string]interface{} {
+	if since.IsZero() {
+		since = time.Unix(0, 0)
+	}
+	return map[string]interface{}{
+		"owner":  owner,
+		"name":   repo,
+		"since":  since.UTC().Format(time.RFC3339),
+		"cursor": nil,
+	}
+}
+
+func historyCommits(h gqlHistory) []Commit {
+	commits := make([]Commit, 0, len(h.Nodes))
+	for _, n := range h.Nodes {
+		var c Commit
+		c.SHA = n.OID
+		c.Commit.Author = n.Author.identity()
+		c.Commit.Committer = n.Committer.identity()
+		c.Commit.Message = n.Message
+		c.Author = n.Author.user()
+		c.Committer = n.Committer.user()
+		for _, p := range n.Parents.Nodes {
+			c.Parents = append(c.Parents, CommitRef{SHA: p.OID})
+		}
+		c.Stats = &CommitStats{Additions: n.Additions, Deletions: n.Deletions, Total: n.Additions + n.Deletions}
+		commits = append(commits, c)
+	}
+	return commits
+}
+
+func (g *GraphQLClient) GetRepo(ctx context.Context, owner, repo string) (*Repo, error) {
+	s, err := g.Snapshot(ctx, owner, repo)
+	if err != nil {
+		return nil, err
+	}
+	r := s.Repo
+	return &r, nil
+}
+
+// GetCommits returns the default branch commits selected by opts, reusing
+// the snapshot's history when it covers opts.Since. Every commit comes with
+// its Stats, but never with Files. History of another ref, or filtered by
+// path or author, is fetched over REST.
+func (g *GraphQLClient) GetCommits(ctx context.Context, owner, repo string, opts CommitOptions) ([]Commit, error) {
+	if opts.SHA != "" || opts.Path != "" || opts.Author != "" {
+		return g.Client.GetCommits(ctx, owner, repo, opts)
+	}
+
+	s, err := g.Snapshot(ctx, owner, repo)
+	if err != nil {
+		return nil, err
+	}
+	commits, err := s.Commits, s.HistoryErr
+	if opts.Since.Before(s.HistorySince) {
+		commits, err = g.history(ctx, historyVars(owner, repo, opts.Since), nil)
+	}
+
+	var selected []Commit
+	for _, c := range commits {
+		date := c.Commit.Author.Date
+		if date.Before(opts.Since) || (!opts.Until.IsZero() && date.After(opts.Until)) {
+			continue
+		}
+		selected = append(selected, c)
+	}
+	return selected, err
+}
+
+// GetContributors ranks the authors of the snapshot's commit history. GraphQL
+// has no contributors connection, so unlike the REST endpoint this only
+// covers the last snapshotHistoryDays days.
+func (g *GraphQLClient) GetContributors(ctx context.Context, owner, repo string) ([]Contributor, error) {
+	s, err := g.Snapshot(ctx, owner, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int)
+	for _, c := range s.Commits {
+		switch {
+		case c.Author != nil && c.Author.Login != "":
+			counts[c.Author.Login]++
+		case c.Commit.Author.Name != "":
+			counts[c.Commit.Author.Name]++
+		}
+	}
+
+	contributors := make([]Contributor, 0, len(counts))
+	for login, n := range counts {
+		contributors = append(contributors, Contributor{Login: login, Commits: n})
+	}
+	sort.Slice(contributors, func(i, j int) bool {
+		if contributors[i].Commits != contributors[j].Commits {
+			return contributors[i].Commits > contributors[j].Commits
+		}
+		return contributors[i].Login < contributors[j].Login
+	})
+	return contributors, s.HistoryErr
+}
+
+func (g *GraphQLClient) GetLanguages(ctx context.Context, owner, repo string) (map[string]int, error) {
+	s, err := g.Snapshot(ctx, owner, repo)
+	if err != nil {
+		return nil, err
+	}
+	return s.Languages, nil
+}
+
+func (g *GraphQLClient) GetReleases(ctx context.Context, owner, repo string) ([]Release, error) {
+	s, err := g.Snapshot(ctx, owner, repo)
+	if err != nil {
+		return nil, err
+	}
+	return s.Releases, s.ReleasesErr
+}
@@ -0,0 +1,299 @@
+package github_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+func commitNode(oid, login string, at time.Time) map[string]interface{} {
+	author := map[string]interface{}{"name": "Anonymous", "email": "anon@example.com", "date": at, "user": nil}
+	if login != "" {
+		author["name"] = strings.ToUpper(login)
+		author["user"] = map[string]string{"login": login}
+	}
+	return map[string]interface{}{
+		"oid":       oid,
+		"message":   "Change " + oid,
+		"additions": 10,
+		"deletions": 2,
+		"author":    author,
+		"committer": author,
+		"parents":   map[string]interface{}{"nodes": []map[string]string{{"oid": oid + "-parent"}}},
+	}
+}
+
+func history(next bool, nodes ...map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"pageInfo": map[string]interface{}{"hasNextPage": next, "endCursor": "cursor1"},
+		"nodes":    nodes,
+	}
+}
+
+func newGraphQLServer(t *testing.T, queries *int) *httptest.Server {
+	recent := time.Now().AddDate(0, 0, -3)
+	older := time.Now().AddDate(0, 0, -40)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/graphql" || r.Method != http.MethodPost {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		*queries++
+
+		var req struct {
+			Query     string                 `json:"query"`
+			Variables map[string]interface{} `json:"variables"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+
+		var repo map[string]interface{}
+		switch {
+		case req.Variables["name"] == "missing":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"data":   map[string]interface{}{"repository": nil},
+				"errors": []map[string]string{{"type": "NOT_FOUND", "message": "Could not resolve to a Repository"}},
+			})
+			return
+		case req.Variables["cursor"] == "cursor1":
+			repo = map[string]interface{}{
+				"defaultBranchRef": map[string]interface{}{"target": map[string]interface{}{
+					"history": history(false, commitNode("c3", "", older)),
+				}},
+			}
+		default:
+			repo = map[string]interface{}{
+				"name":           "busy",
+				"nameWithOwner":  "octo-org/busy",
+				"description":    "A busy project",
+				"stargazerCount": 1280,
+				"forkCount":      214,
+				"createdAt":      "2015-03-02T10:00:00Z",
+				"defaultBranchRef": map[string]interface{}{"name": "main", "target": map[string]interface{}{
+					"history": history(true, commitNode("c1", "alice", recent), commitNode("c2", "alice", older)),
+				}},
+				"openIssues": map[string]int{"totalCount": 7},
+				"openPRs":    map[string]int{"totalCount": 5},
+				"languages": map[string]interface{}{"edges": []map[string]interface{}{
+					{"size": 1000, "node": map[string]string{"name": "Go"}},
+					{"size": 10, "node": map[string]string{"name": "Shell"}},
+				}},
+				"releases": map[string]interface{}{"nodes": []map[string]interface{}{
+					{"tagName": "v1.2.0", "name": "v1.2.0", "isDraft": false, "isPrerelease": false,
+						"createdAt": "2026-06-01T00:00:00Z", "publishedAt": "2026-06-01T00:00:00Z"},
+				}},
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{"repository": repo}})
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestGraphQLSource(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "test-token")
+	ctx := context.Background()
+	queries := 0
+	srv := newGraphQLServer(t, &queries)
+
+	source, err := github.NewSource(github.NewClient(github.WithBaseURL(srv.URL), github.WithMaxRetries(0)), github.BackendGraphQL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo, err := source.GetRepo(ctx, "octo-org", "busy")
+	if err != nil {
+		t.Fatalf("GetRepo: %v", err)
+	}
+	if repo.FullName != "octo-org/busy" || repo.Stars != 1280 || repo.OpenIssues != 12 || repo.DefaultBranch != "main" {
+		t.Errorf("GetRepo = %+v", repo)
+	}
+
+	langs, _ := source.GetLanguages(ctx, "octo-org", "busy")
+	releases, _ := source.GetReleases(ctx, "octo-org", "busy")
+	if langs["Go"] != 1000 || len(releases) != 1 || releases[0].TagName != "v1.2.0" {
+		t.Errorf("languages = %v, releases = %+v", langs, releases)
+	}
+
+	all, _ := source.GetCommits(ctx, "octo-org", "busy", github.LastDays(365))
+	lastWeek, _ := source.GetCommits(ctx, "octo-org", "busy", github.LastDays(7))
+	if len(all) != 3 || len(lastWeek) != 1 {
+		t.Errorf("got %d commits in a year and %d in a week, want 3 and 1", len(all), len(lastWeek))
+	}
+	if c := all[0]; c.Stats == nil || c.Stats.Total != 12 || len(c.Parents) != 1 || c.Commit.Message != "Change c1" || c.Committer == nil {
+		t.Errorf("commit details = %+v", c)
+	}
+
+	contributors, _ := source.GetContributors(ctx, "octo-org", "busy")
+	if len(contributors) != 2 || contributors[0].Login != "alice" || contributors[0].Commits != 2 {
+		t.Errorf("contributors = %+v", contributors)
+	}
+
+	// One batched query plus one for the second history page.
+	if queries != 2 {
+		t.Errorf("made %d GraphQL queries, want 2", queries)
+	}
+}
+
+func TestGraphQLSourceErrors(t *testing.T) {
+	ctx := context.Background()
+	queries := 0
+	srv := newGraphQLServer(t, &queries)
+
+	t.Setenv("GITHUB_TOKEN", "")
+	source := github.NewGraphQLClient(github.NewClient(github.WithBaseURL(srv.URL)))
+	if _, err := source.GetRepo(ctx, "octo-org", "busy"); !errors.Is(err, github.ErrUnauthorized) {
+		t.Errorf("without token: error = %v, want ErrUnauthorized", err)
+	}
+
+	t.Setenv("GITHUB_TOKEN", "test-token")
+	source = github.NewGraphQLClient(github.NewClient(github.WithBaseURL(srv.URL)))
+	if _, err := source.GetRepo(ctx, "octo-org", "missing"); !errors.Is(err, github.ErrNotFound) {
+		t.Errorf("missing repository: error = %v, want ErrNotFound", err)
+	}
+}
+
+// graphqlRepo is a minimal repository answer with one page of releases,
+// followed by a next page when more is set.
+func graphqlRepo(name string, more bool, tags ...string) map[string]interface{} {
+	var nodes []map[string]interface{}
+	for _, tag := range tags {
+		nodes = append(nodes, map[string]interface{}{"tagName": tag, "createdAt": "2026-06-01T00:00:00Z"})
+	}
+	return map[string]interface{}{"data": map[string]interface{}{"repository": map[string]interface{}{
+		"name":          name,
+		"nameWithOwner": "octo-org/" + name,
+		"releases": map[string]interface{}{
+			"pageInfo": map[string]interface{}{"hasNextPage": more, "endCursor": "next-" + strings.Join(tags, "")},
+			"nodes":    nodes,
+		},
+	}}}
+}
+
+func TestGraphQLReleasePaging(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "test-token")
+	var pages atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Variables map[string]interface{} `json:"variables"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		n := pages.Add(1)
+		if n > 1 && req.Variables["releaseCursor"] == nil {
+			t.Errorf("release page %d asked for without a cursor", n)
+		}
+		name, _ := req.Variables["name"].(string)
+		json.NewEncoder(w).Encode(graphqlRepo(name, name == "many", fmt.Sprintf("v%d.0.0", n)))
+	}))
+	t.Cleanup(srv.Close)
+	source := github.NewGraphQLClient(github.NewClient(github.WithBaseURL(srv.URL), github.WithMaxRetries(0)))
+
+	releases, err := source.GetReleases(context.Background(), "octo-org", "many")
+	if !errors.Is(err, github.ErrTruncated) || len(releases) != 3 || releases[2].TagName != "v3.0.0" {
+		t.Errorf("got %d releases, error %v; want 3 and ErrTruncated", len(releases), err)
+	}
+
+	pages.Store(0)
+	releases, err = source.GetReleases(context.Background(), "octo-org", "few")
+	if err != nil || len(releases) != 1 || pages.Load() != 1 {
+		t.Errorf("got %d releases in %d queries, error %v; want 1 in 1", len(releases), pages.Load(), err)
+	}
+}
+
+func TestGraphQLHistoryPageFails(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "test-token")
+	var queries int
+	good := newGraphQLServer(t, &queries)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if queries > 0 {
+			http.Error(w, `{"message": "Server Error"}`, http.StatusBadGateway)
+			return
+		}
+		resp, err := http.Post(good.URL+"/graphql", "application/json", r.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		w.WriteHeader(resp.StatusCode)
+		io.Copy(w, resp.Body)
+	}))
+	t.Cleanup(srv.Close)
+	source := github.NewGraphQLClient(github.NewClient(github.WithBaseURL(srv.URL), github.WithMaxRetries(0)))
+
+	if _, err := source.GetRepo(context.Background(), "octo-org", "busy"); err != nil {
+		t.Fatalf("GetRepo failed with the history: %v", err)
+	}
+	commits, err := source.GetCommits(context.Background(), "octo-org", "busy", github.LastDays(365))
+	if err == nil || errors.Is(err, github.ErrTruncated) || len(commits) != 2 {
+		t.Errorf("got %d commits, error %v; want the first page and the page's error", len(commits), err)
+	}
+}
+
+func TestGraphQLSnapshotConcurrency(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "test-token")
+	slowStarted, release := make(chan struct{}), make(chan struct{})
+	var busyQueries atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Variables map[string]interface{} `json:"variables"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		name, _ := req.Variables["name"].(string)
+		if name == "slow" {
+			close(slowStarted)
+			<-release
+		} else {
+			busyQueries.Add(1)
+			time.Sleep(20 * time.Millisecond)
+		}
+		json.NewEncoder(w).Encode(graphqlRepo(name, false))
+	}))
+	t.Cleanup(srv.Close)
+	source := github.NewGraphQLClient(github.NewClient(github.WithBaseURL(srv.URL), github.WithMaxRetries(0)))
+	ctx := context.Background()
+
+	slowDone := make(chan error)
+	go func() {
+		_, err := source.Snapshot(ctx, "octo-org", "slow")
+		slowDone <- err
+	}()
+	<-slowStarted
+
+	// Another repository isn't held up by the slow query, and concurrent
+	// calls for it share one query.
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if s, err := source.Snapshot(ctx, "octo-org", "busy"); err != nil || s.Repo.FullName != "octo-org/busy" {
+				t.Errorf("Snapshot = %+v, %v", s, err)
+			}
+		}()
+	}
+	done := make(chan struct{})
+	go func() { wg.Wait(); close(done) }()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("snapshot of busy waited for the slow one")
+	}
+	if n := busyQueries.Load(); n != 1 {
+		t.Errorf("made %d queries for busy, want 1", n)
+	}
+
+	close(release)
+	if err := <-slowDone; err != nil {
+		t.Errorf("slow snapshot: %v", err)
+	}
+}
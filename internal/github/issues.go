@@ -1,12 +1,148 @@
 package github
 
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
 type Issue struct {
-	State string `json:"state"`
+	Number    int        `json:"number"`
+	Title     string     `json:"title"`
+	State     string     `json:"state"` // open or closed
+	User      *User      `json:"user"`
+	Labels    []Label    `json:"labels"`
+	Comments  int        `json:"comments"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	ClosedAt  *time.Time `json:"closed_at"`
+
+	// PullRequest is set when the issue is a pull request; GetIssues drops
+	// those.
+	PullRequest *struct {
+		URL string `json:"url"`
+	} `json:"pull_request,omitempty"`
+
+	// Thread is only known for issues whose comments were asked for with
+	// IssueOptions.Details; HasDetails says which.
+	HasDetails bool      `json:"has_details,omitempty"`
+	Thread     []Comment `json:"thread,omitempty"`
+}
+
+// Label is an issue label.
+type Label struct {
+	Name string `json:"name"`
+}
+
+// Comment is a comment on an issue.
+type Comment struct {
+	User      *User     `json:"user"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// IsBot reports whether u is a bot account.
+func (u *User) IsBot() bool {
+	return u != nil && (u.Type == "Bot" || strings.HasSuffix(u.Login, "[bot]"))
 }
 
-func (c *Client) GetIssues(owner, repo string, state string) ([]Issue, error) {
+// FirstResponseAt returns when someone other than the author, and other than
+// a bot, first commented on the issue. It is false when nobody did, or the
+// comments weren't fetched.
+func (i *Issue) FirstResponseAt() (time.Time, bool) {
+	var first time.Time
+	for _, c := range i.Thread {
+		if c.User.IsBot() || c.CreatedAt.IsZero() {
+			continue
+		}
+		if c.User != nil && i.User != nil && c.User.Login == i.User.Login {
+			continue
+		}
+		if first.IsZero() || c.CreatedAt.Before(first) {
+			first = c.CreatedAt
+		}
+	}
+	return first, !first.IsZero()
+}
+
+// IssueOptions selects the issues GetIssues returns.
+type IssueOptions struct {
+	State string // open, closed or all; "" means all
+
+	// Since only lists issues updated at or after it; zero for no bound.
+	Since time.Time
+
+	// Details fetches the comments of up to this many of the newest issues
+	// that have any, one request each. 0 fetches none.
+	Details int
+}
+
+// GetIssues fetches issues, without pull requests, newest first, following
+// pagination up to issuePageCap pages.
+func (c *Client) GetIssues(ctx context.Context, owner, repo string, opts IssueOptions) ([]Issue, error) {
+	query := url.Values{
+		"state":     {opts.State},
+		"sort":      {"created"},
+		"direction": {"desc"},
+		"per_page":  {strconv.Itoa(perPage)},
+	}
+	if opts.State == "" {
+		query.Set("state", "all")
+	}
+	if !opts.Since.IsZero() {
+		query.Set("since", opts.Since.UTC().Format(time.RFC3339))
+	}
+
 	var issues []Issue
-	url := "https://api.github.com/repos/" + owner + "/" + repo + "/issues?state=" + state
-	err := c.get(url, &issues)
+	err := paginate(ctx, c, c.endpoint("repos/%s/%s/issues?%s", owner, repo, query.Encode()), issuePageCap, func(page []Issue) bool {
+		for _, issue := range page {
+			if issue.PullRequest == nil {
+				issues = append(issues, issue)
+			}
+		}
+		return true
+	})
+	if opts.Details > 0 && len(issues) > 0 {
+		if detailsErr := c.addIssueComments(ctx, owner, repo, issues, opts.Details); err == nil {
+			err = detailsErr
+		}
+	}
 	return issues, err
 }
+
+// addIssueComments fills in the comment thread of the first max issues that
+// have comments. Issues without comments need no request and count as
+// detailed.
+func (c *Client) addIssueComments(ctx context.Context, owner, repo string, issues []Issue, max int) error {
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(statsConcurrency)
+	for i := range issues {
+		issue := &issues[i]
+		if issue.Comments == 0 {
+			issue.HasDetails = true
+			continue
+		}
+		if max == 0 {
+			continue
+		}
+		max--
+		g.Go(func() error {
+			url := c.endpoint("repos/%s/%s/issues/%d/comments?per_page=%d", owner, repo, issue.Number, perPage)
+			// Comments come oldest first, so the first page holds the first
+			// response; a longer thread keeps it instead of failing every
+			// other issue.
+			comments, err := getAll[Comment](ctx, c, url, 1)
+			if err != nil && !errors.Is(err, ErrTruncated) {
+				return err
+			}
+			issue.Thread = comments
+			issue.HasDetails = true
+			return nil
+		})
+	}
+	return g.Wait()
+}
@@ -0,0 +1,47 @@
+package github_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+	"github.com/agnivo988/Repo-lyzer/internal/github/githubtest"
+)
+
+func TestGetIssuesDetails(t *testing.T) {
+	client := githubtest.NewServer(t).Client()
+
+	issues, err := client.GetIssues(context.Background(), "octo-org", "busy", github.IssueOptions{Details: 2})
+	if err != nil {
+		t.Fatalf("GetIssues: %v", err)
+	}
+
+	threads, details := 0, 0
+	for _, issue := range issues {
+		if issue.PullRequest != nil {
+			t.Errorf("#%d is a pull request", issue.Number)
+		}
+		if len(issue.Thread) > 0 {
+			threads++
+		}
+		if issue.HasDetails {
+			details++
+		}
+	}
+	// Two threads were fetched; the twelve open issues have no comments.
+	if threads != 2 || details != 14 {
+		t.Errorf("%d issues have threads and %d details, want 2 and 14", threads, details)
+	}
+
+	// #211 was answered by a bot first, then by bob three hours in; the
+	// second page of its thread is left out.
+	newest := issues[0]
+	if newest.Number != 211 || newest.User.Login != "carol" || newest.Labels[0].Name != "bug" || len(newest.Thread) != 2 {
+		t.Errorf("newest issue = %+v", newest)
+	}
+	first, ok := newest.FirstResponseAt()
+	if !ok || first.Sub(newest.CreatedAt) != 3*time.Hour {
+		t.Errorf("#211 first response at %v, %v", first, ok)
+	}
+}
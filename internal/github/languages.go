@@ -1,7 +1,9 @@
 package github
 
-func (c *Client) GetLanguages(owner, repo string) (map[string]int, error) {
+import "context"
+
+func (c *Client) GetLanguages(ctx context.Context, owner, repo string) (map[string]int, error) {
 	var langs map[string]int
-	err := c.get("https://api.github.com/repos/"+owner+"/"+repo+"/languages", &langs)
+	err := c.get(ctx, c.endpoint("repos/%s/%s/languages", owner, repo), &langs)
 	return langs, err
-}
\ No newline at end of file
+}
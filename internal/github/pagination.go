@@ -0,0 +1,80 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// perPage is the page size requested from every list endpoint. 100 is the
+// maximum GitHub accepts.
+const perPage = 100
+
+// Page caps for the list endpoints, so a single call on a very large
+// repository can't burn through the rate limit. 0 means no cap.
+const (
+	commitPageCap      = 20
+	contributorPageCap = 10
+	issuePageCap       = 10
+	pullPageCap        = 3
+	releasePageCap     = 3
+	tagPageCap         = 3
+	treePageCap        = 1
+)
+
+// paginate walks url and every page linked from it via rel="next", decoding
+// each page into a fresh T and handing it to each, until each returns false.
+// When maxPages > 0 it stops after that many pages, returning ErrTruncated
+// if more were left.
+func paginate[T any](ctx context.Context, c *Client, url string, maxPages int, each func(page T) bool) error {
+	for pages := 0; url != ""; pages++ {
+		if maxPages > 0 && pages >= maxPages {
+			return fmt.Errorf("%w: stopped after %d pages", ErrTruncated, maxPages)
+		}
+
+		var page T
+		next, err := c.getPage(ctx, url, &page)
+		if err != nil {
+			return err
+		}
+		if !each(page) {
+			return nil
+		}
+		url = next
+	}
+	return nil
+}
+
+// getAll collects every item of a paginated list endpoint. On error, the
+// items of the pages fetched so far are returned along with it.
+func getAll[T any](ctx context.Context, c *Client, url string, maxPages int) ([]T, error) {
+	var all []T
+	err := paginate(ctx, c, url, maxPages, func(page []T) bool {
+		all = append(all, page...)
+		return true
+	})
+	return all, err
+}
+
+// nextPageURL extracts the rel="next" target from a Link header such as
+//
+//	<https://api.github.com/...&page=2>; rel="next", <...&page=5>; rel="last"
+func nextPageURL(link string) string {
+	for _, part := range strings.Split(link, ",") {
+		segments := strings.Split(part, ";")
+		if len(segments) < 2 {
+			continue
+		}
+		target := strings.TrimSpace(segments[0])
+		if !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+			continue
+		}
+		for _, param := range segments[1:] {
+			param = strings.TrimSpace(param)
+			if param == `rel="next"` || param == "rel=next" {
+				return target[1 : len(target)-1]
+			}
+		}
+	}
+	return ""
+}
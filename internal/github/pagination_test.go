@@ -0,0 +1,25 @@
+package github
+
+import "testing"
+
+func TestNextPageURL(t *testing.T) {
+	tests := []struct {
+		link string
+		want string
+	}{
+		{"", ""},
+		{`<https://api.github.com/repos/o/r/commits?page=2>; rel="next", <https://api.github.com/repos/o/r/commits?page=5>; rel="last"`,
+			"https://api.github.com/repos/o/r/commits?page=2"},
+		{`<https://api.github.com/repos/o/r/commits?page=1>; rel="prev", <https://api.github.com/repos/o/r/commits?page=1>; rel="first"`,
+			""},
+		{`<https://api.github.com/x?after=abc>; rel="last", <https://api.github.com/x?after=def>; rel="next"`,
+			"https://api.github.com/x?after=def"},
+		{`garbage; rel="next"`, ""},
+	}
+
+	for _, tt := range tests {
+		if got := nextPageURL(tt.link); got != tt.want {
+			t.Errorf("nextPageURL(%q) = %q, want %q", tt.link, got, tt.want)
+		}
+	}
+}
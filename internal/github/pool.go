@@ -0,0 +1,183 @@
+package github
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// tokenPool holds the credentials a client may authenticate with. With more
+// than one member, every request goes out with the member that has the most
+// quota left for its rate limit resource.
+type tokenPool struct {
+	members []Credentials
+	limits  *rateTracker
+}
+
+func (p *tokenPool) size() int {
+	if p == nil {
+		return 0
+	}
+	return len(p.members)
+}
+
+// pick returns the member to send a request for resource with and the
+// Authorization header value to use. Members whose quota is unknown are
+// preferred, so each gets probed; once every member is exhausted the one
+// that resets first is returned.
+func (p *tokenPool) pick(ctx context.Context, resource string) (Credentials, string, error) {
+	var (
+		best      Credentials
+		bestAuth  string
+		bestScore = -1
+		bestReset time.Time
+		firstErr  error
+	)
+	for _, member := range p.members {
+		token, err := member.Token(ctx)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		auth := "Bearer " + token
+
+		score, reset := math.MaxInt, time.Time{}
+		if state, ok := p.limits.get(authKey(auth), resource); ok && time.Now().Before(state.Reset) {
+			score, reset = state.Remaining, state.Reset
+		}
+		if score > bestScore || (score == 0 && bestScore == 0 && reset.Before(bestReset)) {
+			best, bestAuth, bestScore, bestReset = member, auth, score, reset
+		}
+	}
+	if best == nil {
+		return nil, "", firstErr
+	}
+	return best, bestAuth, nil
+}
+
+// hasQuota reports whether a member other than the one sending auth has
+// quota left for resource, or has not been tried yet.
+func (p *tokenPool) hasQuota(ctx context.Context, resource, auth string) bool {
+	if p.size() < 2 {
+		return false
+	}
+	_, other, err := p.pick(ctx, resource)
+	if err != nil || other == auth {
+		return false
+	}
+	state, ok := p.limits.get(authKey(other), resource)
+	return !ok || state.Remaining > 0 || !time.Now().Before(state.Reset)
+}
+
+// identity names the credentials of the pool for the response cache. It
+// covers the token values of static credentials and the sources of the
+// others, whose tokens rotate.
+func (p *tokenPool) identity() string {
+	if p.size() == 0 {
+		return ""
+	}
+	var ids []string
+	for _, member := range p.members {
+		if s, ok := member.(staticToken); ok {
+			ids = append(ids, s.token)
+		} else {
+			ids = append(ids, member.Source())
+		}
+	}
+	return strings.Join(ids, "\x00")
+}
+
+// authKey is the key quota is tracked under for an Authorization header
+// value: a short hash of it, or "" for anonymous requests.
+func authKey(auth string) string {
+	if auth == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(auth))
+	return hex.EncodeToString(sum[:8])
+}
+
+// authorize returns req carrying the Authorization header of the pool
+// member it should be sent with. Requests that already carry one, such as
+// the GitHub App token exchange, are left alone.
+func (p *tokenPool) authorize(req *http.Request, resource string) (*http.Request, string, error) {
+	if auth := req.Header.Get("Authorization"); auth != "" || p.size() == 0 {
+		return req, auth, nil
+	}
+	_, auth, err := p.pick(req.Context(), resource)
+	if err != nil {
+		return nil, "", err
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", auth)
+	return req, auth, nil
+}
+
+// TokenQuota is the core API quota of one of a client's tokens.
+type TokenQuota struct {
+	Source    string // where the token comes from, "" when unauthenticated
+	Limit     int
+	Remaining int
+	Reset     time.Time
+}
+
+// Quotas reports the core API quota of each token the client may use, or of
+// anonymous access when it has none. Quota already seen in response headers
+// is reported as is; the others are asked from /rate_limit, which does not
+// count against them.
+func (c *Client) Quotas(ctx context.Context) ([]TokenQuota, error) {
+	if c.authErr != nil {
+		return nil, c.authErr
+	}
+	if c.pool.size() == 0 {
+		quota, err := c.quota(ctx, "")
+		return []TokenQuota{quota}, err
+	}
+
+	var quotas []TokenQuota
+	var errs []error
+	for _, member := range c.pool.members {
+		token, err := member.Token(ctx)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		quota, err := c.quota(ctx, "Bearer "+token)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		quota.Source = member.Source()
+		quotas = append(quotas, quota)
+	}
+	return quotas, errors.Join(errs...)
+}
+
+// quota returns the core quota of requests sent with the Authorization
+// header value auth.
+func (c *Client) quota(ctx context.Context, auth string) (TokenQuota, error) {
+	if state, ok := c.limits.get(authKey(auth), "core"); ok {
+		return TokenQuota{Limit: state.Limit, Remaining: state.Remaining, Reset: state.Reset}, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", c.endpoint("rate_limit"), nil)
+	if err != nil {
+		return TokenQuota{}, err
+	}
+	if auth != "" {
+		req.Header.Set("Authorization", auth)
+	}
+	var rateLimit RateLimit
+	if _, err := c.do(req, &rateLimit); err != nil {
+		return TokenQuota{}, err
+	}
+	core := rateLimit.Resources.Core
+	return TokenQuota{Limit: core.Limit, Remaining: core.Remaining, Reset: time.Unix(int64(core.Reset), 0)}, nil
+}
@@ -0,0 +1,141 @@
+package github_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+// quotaServer answers every request with the remaining quota configured for
+// its token, rate limiting tokens that have none left. It records which
+// token each request used.
+type quotaServer struct {
+	*httptest.Server
+
+	mu   sync.Mutex
+	used []string
+}
+
+func newQuotaServer(t *testing.T, remaining map[string]int) *quotaServer {
+	s := &quotaServer{}
+	reset := strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		s.mu.Lock()
+		s.used = append(s.used, token)
+		s.mu.Unlock()
+
+		left := remaining[token]
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(left))
+		w.Header().Set("X-RateLimit-Reset", reset)
+		if left == 0 {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"message": "API rate limit exceeded"}`))
+			return
+		}
+		if r.URL.Path == "/rate_limit" {
+			w.Write([]byte(`{"resources": {"core": {"limit": 5000, "remaining": ` + strconv.Itoa(left) + `, "reset": ` + reset + `}}}`))
+			return
+		}
+		w.Write([]byte(`{"full_name": "o/r"}`))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func poolClient(s *quotaServer, tokens ...string) *github.Client {
+	var resolvers []github.Resolver
+	for _, token := range tokens {
+		resolvers = append(resolvers, github.FromToken(token, token))
+	}
+	return github.NewClient(
+		github.WithBaseURL(s.URL),
+		github.WithMaxRetries(0),
+		github.WithMaxRateLimitWait(0),
+		github.WithTokenPool(resolvers...),
+	)
+}
+
+func TestTokenPool(t *testing.T) {
+	tests := []struct {
+		name      string
+		remaining map[string]int
+		requests  int
+		wantUsed  string
+		wantErr   error
+	}{
+		{
+			name:      "probes each token, then prefers the fullest",
+			remaining: map[string]int{"a": 10, "b": 4000},
+			requests:  3,
+			wantUsed:  "a b b",
+		},
+		{
+			name:      "moves on from an exhausted token",
+			remaining: map[string]int{"a": 0, "b": 4000},
+			requests:  2,
+			wantUsed:  "a b b",
+		},
+		{
+			name:      "fails once every token is exhausted",
+			remaining: map[string]int{"a": 0, "b": 0},
+			requests:  1,
+			wantUsed:  "a b",
+			wantErr:   github.ErrRateLimited,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newQuotaServer(t, tt.remaining)
+			client := poolClient(srv, "a", "b")
+
+			for i := 0; i < tt.requests; i++ {
+				_, err := client.GetRepo(context.Background(), "o", "r")
+				if !errors.Is(err, tt.wantErr) || (err != nil) != (tt.wantErr != nil) {
+					t.Fatalf("request %d: error = %v, want %v", i, err, tt.wantErr)
+				}
+			}
+			if got := strings.Join(srv.used, " "); got != tt.wantUsed {
+				t.Errorf("tokens used = %q, want %q", got, tt.wantUsed)
+			}
+		})
+	}
+}
+
+func TestTokenPoolQuotas(t *testing.T) {
+	srv := newQuotaServer(t, map[string]int{"a": 10, "b": 4000})
+	client := poolClient(srv, "a", "b")
+
+	if _, err := client.GetRepo(context.Background(), "o", "r"); err != nil {
+		t.Fatalf("GetRepo: %v", err)
+	}
+	quotas, err := client.Quotas(context.Background())
+	if err != nil {
+		t.Fatalf("Quotas: %v", err)
+	}
+
+	var got []string
+	for _, q := range quotas {
+		got = append(got, q.Source+"="+strconv.Itoa(q.Remaining))
+	}
+	if strings.Join(got, " ") != "a=10 b=4000" {
+		t.Errorf("Quotas = %v", got)
+	}
+	// a was known from the GetRepo response; only b needed /rate_limit.
+	if len(srv.used) != 2 {
+		t.Errorf("sent %d requests, want 2", len(srv.used))
+	}
+	if client.AuthSource() != "a, b" {
+		t.Errorf("AuthSource() = %q", client.AuthSource())
+	}
+}
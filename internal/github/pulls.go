@@ -0,0 +1,136 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+type PullRequest struct {
+	Number    int        `json:"number"`
+	Title     string     `json:"title"`
+	State     string     `json:"state"` // open or closed
+	Draft     bool       `json:"draft"`
+	User      *User      `json:"user"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	ClosedAt  *time.Time `json:"closed_at"`
+	MergedAt  *time.Time `json:"merged_at"`
+
+	// The fields below are only known for pull requests whose details were
+	// asked for with PullRequestOptions.Details; HasDetails says which.
+	HasDetails   bool     `json:"has_details,omitempty"`
+	MergedBy     *User    `json:"merged_by"`
+	Additions    int      `json:"additions"`
+	Deletions    int      `json:"deletions"`
+	ChangedFiles int      `json:"changed_files"`
+	Reviews      []Review `json:"reviews,omitempty"`
+}
+
+// Review is a submitted pull request review.
+type Review struct {
+	User        *User     `json:"user"`
+	State       string    `json:"state"` // APPROVED, CHANGES_REQUESTED, COMMENTED, ...
+	SubmittedAt time.Time `json:"submitted_at"`
+}
+
+// Merged reports whether the pull request was merged.
+func (p *PullRequest) Merged() bool {
+	return p.MergedAt != nil
+}
+
+// FirstReviewAt returns when someone other than the author, and other than
+// a bot, first reviewed the pull request. It is false when there was no such
+// review, or the reviews weren't fetched.
+func (p *PullRequest) FirstReviewAt() (time.Time, bool) {
+	var first time.Time
+	for _, r := range p.Reviews {
+		if r.State == "PENDING" || r.User.IsBot() || r.SubmittedAt.IsZero() {
+			continue
+		}
+		if r.User != nil && p.User != nil && r.User.Login == p.User.Login {
+			continue
+		}
+		if first.IsZero() || r.SubmittedAt.Before(first) {
+			first = r.SubmittedAt
+		}
+	}
+	return first, !first.IsZero()
+}
+
+// PullRequestOptions selects the pull requests GetPullRequests returns.
+type PullRequestOptions struct {
+	State string // open, closed or all; "" means all
+
+	// Only pull requests created in this window; zero for no bound.
+	Since, Until time.Time
+
+	// Details fetches the size and reviews of up to this many of the newest
+	// pull requests, two requests each. 0 fetches none.
+	Details int
+}
+
+// GetPullRequests fetches pull requests newest first, following pagination
+// up to pullPageCap pages or until they are older than opts.Since.
+func (c *Client) GetPullRequests(ctx context.Context, owner, repo string, opts PullRequestOptions) ([]PullRequest, error) {
+	state := opts.State
+	if state == "" {
+		state = "all"
+	}
+	url := c.endpoint("repos/%s/%s/pulls?state=%s&sort=created&direction=desc&per_page=%d", owner, repo, state, perPage)
+
+	var prs []PullRequest
+	err := paginate(ctx, c, url, pullPageCap, func(page []PullRequest) bool {
+		for _, pr := range page {
+			if !opts.Since.IsZero() && pr.CreatedAt.Before(opts.Since) {
+				return false
+			}
+			if opts.Until.IsZero() || !pr.CreatedAt.After(opts.Until) {
+				prs = append(prs, pr)
+			}
+		}
+		return true
+	})
+	if opts.Details > 0 && len(prs) > 0 {
+		if detailsErr := c.addPullRequestDetails(ctx, owner, repo, prs, opts.Details); err == nil {
+			err = detailsErr
+		}
+	}
+	return prs, err
+}
+
+// addPullRequestDetails fills in the size and reviews of the first max pull
+// requests.
+func (c *Client) addPullRequestDetails(ctx context.Context, owner, repo string, prs []PullRequest, max int) error {
+	if max > len(prs) {
+		max = len(prs)
+	}
+
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(statsConcurrency)
+	for i := range prs[:max] {
+		pr := &prs[i]
+		g.Go(func() error {
+			number := strconv.Itoa(pr.Number)
+			var detail PullRequest
+			if err := c.get(ctx, c.endpoint("repos/%s/%s/pulls/%s", owner, repo, number), &detail); err != nil {
+				return err
+			}
+			// One page of reviews is enough to know the first one; a longer
+			// review thread keeps its first page instead of failing every
+			// other pull request.
+			reviews, err := getAll[Review](ctx, c, c.endpoint("repos/%s/%s/pulls/%s/reviews?per_page=%d", owner, repo, number, perPage), 1)
+			if err != nil && !errors.Is(err, ErrTruncated) {
+				return err
+			}
+			pr.MergedBy, pr.Additions, pr.Deletions, pr.ChangedFiles = detail.MergedBy, detail.Additions, detail.Deletions, detail.ChangedFiles
+			pr.Reviews = reviews
+			pr.HasDetails = true
+			return nil
+		})
+	}
+	return g.Wait()
+}
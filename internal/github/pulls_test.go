@@ -0,0 +1,78 @@
+package github_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+	"github.com/agnivo988/Repo-lyzer/internal/github/githubtest"
+)
+
+func TestGetPullRequests(t *testing.T) {
+	client := githubtest.NewServer(t).Client()
+	may := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		opts        github.PullRequestOptions
+		wantNumbers []int
+		wantDetails int
+	}{
+		{"all, no details", github.PullRequestOptions{}, []int{109, 108, 107, 106, 105, 104, 103, 102, 101, 110}, 0},
+		{"since stops early", github.PullRequestOptions{Since: may}, []int{109, 108, 107, 106}, 0},
+		{"until", github.PullRequestOptions{Since: may, Until: may.AddDate(0, 1, 0)}, []int{107, 106}, 0},
+		{"details for the newest", github.PullRequestOptions{Since: may, Details: 3}, []int{109, 108, 107, 106}, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prs, err := client.GetPullRequests(context.Background(), "octo-org", "busy", tt.opts)
+			if err != nil {
+				t.Fatalf("GetPullRequests: %v", err)
+			}
+			if len(prs) != len(tt.wantNumbers) {
+				t.Fatalf("got %d pull requests, want %d", len(prs), len(tt.wantNumbers))
+			}
+			details := 0
+			for i, pr := range prs {
+				if pr.Number != tt.wantNumbers[i] {
+					t.Errorf("pull request %d is #%d, want #%d", i, pr.Number, tt.wantNumbers[i])
+				}
+				if pr.HasDetails {
+					details++
+				}
+			}
+			if details != tt.wantDetails {
+				t.Errorf("%d pull requests have details, want %d", details, tt.wantDetails)
+			}
+		})
+	}
+}
+
+func TestPullRequestDetails(t *testing.T) {
+	client := githubtest.NewServer(t).Client()
+
+	prs, err := client.GetPullRequests(context.Background(), "octo-org", "busy", github.PullRequestOptions{Details: 3})
+	if err != nil {
+		t.Fatalf("GetPullRequests: %v", err)
+	}
+
+	// #109 was closed unmerged after review, #108 has a second page of
+	// reviews that is left out, #107 merged after a bot's review and then
+	// bob's.
+	closed, long, merged := prs[0], prs[1], prs[2]
+	if closed.Merged() || closed.State != "closed" || closed.Additions+closed.Deletions != 60 {
+		t.Errorf("#%d = %+v", closed.Number, closed)
+	}
+	if !long.HasDetails || len(long.Reviews) != 1 {
+		t.Errorf("#%d has details %v and %d reviews, want the first page", long.Number, long.HasDetails, len(long.Reviews))
+	}
+	if !merged.Merged() || merged.MergedBy == nil || merged.MergedBy.Login != "bob" || len(merged.Reviews) != 2 {
+		t.Errorf("#%d = %+v", merged.Number, merged)
+	}
+	first, ok := merged.FirstReviewAt()
+	if !ok || first.Sub(merged.CreatedAt) != 5*time.Hour {
+		t.Errorf("#%d first review at %v, %v", merged.Number, first, ok)
+	}
+}
@@ -1,8 +1,10 @@
 package github
 
 import (
+	"context"
 	"time"
 )
+
 type RateLimit struct {
 	Resources struct {
 		Core struct {
@@ -12,14 +14,34 @@ type RateLimit struct {
 		} `json:"core"`
 	} `json:"resources"`
 }
-func (c *Client) GetRateLimit() (*RateLimit, error) {
-	var rateLimit RateLimit
-	err := c.get("https://api.github.com/rate_limit", &rateLimit)
+
+// GetRateLimit reports the core API quota of the token the next request
+// would be sent with. It is answered from the rate limit headers of earlier
+// responses when there are any, and only asks the /rate_limit endpoint on a
+// fresh client. Quotas reports every token of a pool.
+func (c *Client) GetRateLimit(ctx context.Context) (*RateLimit, error) {
+	if c.authErr != nil {
+		return nil, c.authErr
+	}
+	auth := ""
+	if c.pool.size() > 0 {
+		var err error
+		if _, auth, err = c.pool.pick(ctx, "core"); err != nil {
+			return nil, err
+		}
+	}
+
+	quota, err := c.quota(ctx, auth)
 	if err != nil {
 		return nil, err
 	}
+	var rateLimit RateLimit
+	rateLimit.Resources.Core.Limit = quota.Limit
+	rateLimit.Resources.Core.Remaining = quota.Remaining
+	rateLimit.Resources.Core.Reset = int(quota.Reset.Unix())
 	return &rateLimit, nil
 }
+
 func (r *RateLimit) ResetTime() time.Time {
 	return time.Unix(int64(r.Resources.Core.Reset), 0)
 }
@@ -0,0 +1,35 @@
+package github
+
+import (
+	"context"
+	"time"
+)
+
+type Release struct {
+	TagName     string    `json:"tag_name"`
+	Name        string    `json:"name"`
+	Body        string    `json:"body"` // release notes
+	Draft       bool      `json:"draft"`
+	Prerelease  bool      `json:"prerelease"`
+	CreatedAt   time.Time `json:"created_at"`
+	PublishedAt time.Time `json:"published_at"`
+}
+
+// Tag is a git tag. Unlike releases, tags carry no date of their own.
+type Tag struct {
+	Name   string    `json:"name"`
+	Commit CommitRef `json:"commit"`
+}
+
+// GetReleases fetches the releases of a repository, newest first.
+func (c *Client) GetReleases(ctx context.Context, owner, repo string) ([]Release, error) {
+	url := c.endpoint("repos/%s/%s/releases?per_page=%d", owner, repo, perPage)
+	return getAll[Release](ctx, c, url, releasePageCap)
+}
+
+// GetTags fetches the tags of a repository, in the reverse order of their
+// names.
+func (c *Client) GetTags(ctx context.Context, owner, repo string) ([]Tag, error) {
+	url := c.endpoint("repos/%s/%s/tags?per_page=%d", owner, repo, perPage)
+	return getAll[Tag](ctx, c, url, tagPageCap)
+}
@@ -1,20 +1,33 @@
 package github
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 type Repo struct {
-	Name        string `json:"name"`
-	FullName    string `json:"full_name"`
-	Stars       int    `json:"stargazers_count"`
-	Forks       int    `json:"forks_count"`
-	OpenIssues  int    `json:"open_issues_count"`
-	Description string `json:"description"`
-	CreatedAt   time.Time `json:"created_at"`
-	DefaultBranch string `json:"default_branch"`
+	Name          string    `json:"name"`
+	FullName      string    `json:"full_name"`
+	Stars         int       `json:"stargazers_count"`
+	Forks         int       `json:"forks_count"`
+	OpenIssues    int       `json:"open_issues_count"`
+	Description   string    `json:"description"`
+	CreatedAt     time.Time `json:"created_at"`
+	DefaultBranch string    `json:"default_branch"`
+	// License is the license GitHub detected, or nil when it found none.
+	License *License `json:"license"`
 }
 
-func (c *Client) GetRepo(owner, repo string) (*Repo, error) {
+// License is a license as GitHub identifies it. SPDXID is NOASSERTION for
+// license files GitHub could not match.
+type License struct {
+	Key    string `json:"key"`
+	Name   string `json:"name"`
+	SPDXID string `json:"spdx_id"`
+}
+
+func (c *Client) GetRepo(ctx context.Context, owner, repo string) (*Repo, error) {
 	var r Repo
-	err := c.get("https://api.github.com/repos/"+owner+"/"+repo, &r)
+	err := c.get(ctx, c.endpoint("repos/%s/%s", owner, repo), &r)
 	return &r, err
 }
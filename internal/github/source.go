@@ -0,0 +1,45 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// RepoSource is everything an analysis needs to know about a repository.
+// The REST Client and the GraphQLClient both implement it, so analysis code
+// does not depend on which API the data comes from.
+type RepoSource interface {
+	GetRepo(ctx context.Context, owner, repo string) (*Repo, error)
+	GetCommits(ctx context.Context, owner, repo string, opts CommitOptions) ([]Commit, error)
+	GetContributors(ctx context.Context, owner, repo string) ([]Contributor, error)
+	GetLanguages(ctx context.Context, owner, repo string) (map[string]int, error)
+	GetFileTree(ctx context.Context, owner, repo, branch string) ([]TreeEntry, error)
+	GetIssues(ctx context.Context, owner, repo string, opts IssueOptions) ([]Issue, error)
+	GetPullRequests(ctx context.Context, owner, repo string, opts PullRequestOptions) ([]PullRequest, error)
+	GetReleases(ctx context.Context, owner, repo string) ([]Release, error)
+	GetTags(ctx context.Context, owner, repo string) ([]Tag, error)
+	GetCommunityProfile(ctx context.Context, owner, repo string) (*CommunityProfile, error)
+	GetLicenseFile(ctx context.Context, owner, repo string) (*LicenseFile, error)
+	GetFileContent(ctx context.Context, owner, repo, path, ref string) ([]byte, error)
+	GetBranch(ctx context.Context, owner, repo, branch string) (*Branch, error)
+	GetBranchProtection(ctx context.Context, owner, repo, branch string) (*BranchProtection, error)
+}
+
+// Names of the available backends, as accepted by NewSource.
+const (
+	BackendREST    = "rest"
+	BackendGraphQL = "graphql"
+)
+
+// NewSource returns the RepoSource for backend on top of c. An empty backend
+// selects REST.
+func NewSource(c *Client, backend string) (RepoSource, error) {
+	switch strings.ToLower(backend) {
+	case "", BackendREST:
+		return c, nil
+	case BackendGraphQL:
+		return NewGraphQLClient(c), nil
+	}
+	return nil, fmt.Errorf("unknown backend %q (want %s or %s)", backend, BackendREST, BackendGraphQL)
+}
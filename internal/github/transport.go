@@ -0,0 +1,309 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Retry defaults for the rate-limit aware transport.
+const (
+	DefaultMaxRetries       = 3
+	DefaultMaxRateLimitWait = time.Minute
+
+	baseBackoff = time.Second
+	maxBackoff  = 30 * time.Second
+)
+
+// rateState is the quota of one rate limit resource as reported by the
+// X-RateLimit-* response headers.
+type rateState struct {
+	Limit     int
+	Remaining int
+	Used      int
+	Reset     time.Time
+}
+
+// rateKey identifies a quota: the credentials it belongs to (see authKey)
+// and the resource ("core", "search", "graphql", ...).
+type rateKey struct {
+	auth     string
+	resource string
+}
+
+// rateTracker remembers the latest rateState seen for each quota.
+type rateTracker struct {
+	mu     sync.Mutex
+	states map[rateKey]rateState
+}
+
+func newRateTracker() *rateTracker {
+	return &rateTracker{states: make(map[rateKey]rateState)}
+}
+
+func (t *rateTracker) update(auth string, h http.Header) {
+	state, ok := parseRateState(h)
+	if !ok {
+		return
+	}
+	resource := h.Get("X-RateLimit-Resource")
+	if resource == "" {
+		resource = "core"
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.states[rateKey{auth, resource}] = state
+}
+
+func (t *rateTracker) get(auth, resource string) (rateState, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	state, ok := t.states[rateKey{auth, resource}]
+	return state, ok
+}
+
+// rateLimitTransport is an http.RoundTripper that authenticates requests
+// with the pool member that has the most quota left, tracks quota headers on
+// every response, holds requests back while a known quota is exhausted and
+// retries rate-limited and transient failures with jittered backoff. A
+// request rate limited on one token is retried at once on another that
+// still has quota.
+type rateLimitTransport struct {
+	base       http.RoundTripper
+	limits     *rateTracker
+	pool       *tokenPool
+	timeout    time.Duration // per attempt, including reading the body
+	maxRetries int
+	maxWait    time.Duration // longest we are willing to sleep for a quota reset
+}
+
+func (t *rateLimitTransport) RoundTrip(orig *http.Request) (*http.Response, error) {
+	ctx := orig.Context()
+	resource := resourceFor(orig)
+
+	for attempt, switches := 0, 0; ; attempt++ {
+		req, auth, err := t.pool.authorize(orig, resource)
+		if err != nil {
+			return nil, err
+		}
+		if err := t.waitForQuota(ctx, authKey(auth), resource); err != nil {
+			return nil, err
+		}
+
+		resp, err := t.attempt(req)
+		if err != nil {
+			if ctx.Err() != nil || attempt >= t.maxRetries || !replayable(req) {
+				return nil, err
+			}
+			if err := sleep(ctx, backoff(attempt)); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		t.limits.update(authKey(auth), resp.Header)
+
+		// Moving to another token beats waiting for this one to reset,
+		// and does not count as a retry.
+		if switches < t.pool.size() && replayable(req) && resp.StatusCode != http.StatusOK &&
+			isRateLimited(resp, peekBody(resp)) && t.pool.hasQuota(ctx, resource, auth) {
+			switches++
+			attempt--
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+			continue
+		}
+
+		delay, retry := t.retryDelay(resp, attempt)
+		if !retry || attempt >= t.maxRetries || !replayable(req) {
+			return resp, nil
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		if err := sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// attempt sends req once under the per-attempt timeout. The timeout stays
+// armed until the response body is closed.
+func (t *rateLimitTransport) attempt(req *http.Request) (*http.Response, error) {
+	if t.timeout <= 0 {
+		return t.base.RoundTrip(req)
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), t.timeout)
+	resp, err := t.base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
+
+// waitForQuota blocks until the tracked quota of resource resets when it is
+// known to be exhausted. Waits longer than maxWait are not attempted; the
+// request goes out and GitHub's rate limit response is surfaced instead.
+func (t *rateLimitTransport) waitForQuota(ctx context.Context, auth, resource string) error {
+	state, ok := t.limits.get(auth, resource)
+	if !ok || state.Remaining > 0 {
+		return nil
+	}
+	wait := time.Until(state.Reset)
+	if wait <= 0 || wait > t.maxWait {
+		return nil
+	}
+	return sleep(ctx, wait+jitter(time.Second))
+}
+
+// retryDelay decides whether resp is worth retrying and after how long.
+func (t *rateLimitTransport) retryDelay(resp *http.Response, attempt int) (time.Duration, bool) {
+	switch {
+	case resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests:
+		if !isRateLimited(resp, peekBody(resp)) {
+			return 0, false
+		}
+		if wait, ok := retryAfter(resp.Header); ok {
+			return wait, wait <= t.maxWait
+		}
+		if state, ok := parseRateState(resp.Header); ok && state.Remaining == 0 {
+			wait := time.Until(state.Reset) + jitter(time.Second)
+			return wait, wait <= t.maxWait
+		}
+		// Secondary rate limit without guidance: GitHub asks clients to
+		// wait at least a minute.
+		return time.Minute + jitter(5*time.Second), time.Minute <= t.maxWait
+	case resp.StatusCode >= 500 && resp.StatusCode != http.StatusNotImplemented:
+		return backoff(attempt), true
+	}
+	return 0, false
+}
+
+// isRateLimited reports whether a 403/429 response is a primary or
+// secondary rate limit rather than a permissions problem.
+func isRateLimited(resp *http.Response, message string) bool {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return false
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+	if _, ok := retryAfter(resp.Header); ok {
+		return true
+	}
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		return true
+	}
+	return strings.Contains(strings.ToLower(message), "rate limit")
+}
+
+// peekBody returns the (truncated) response body while leaving it readable
+// for the caller.
+func peekBody(resp *http.Response) string {
+	body := resp.Body
+	data, err := io.ReadAll(io.LimitReader(body, 64<<10))
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{bytes.NewReader(data), body}
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+func parseRateState(h http.Header) (rateState, bool) {
+	remaining, err := strconv.Atoi(h.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return rateState{}, false
+	}
+	limit, _ := strconv.Atoi(h.Get("X-RateLimit-Limit"))
+	used, _ := strconv.Atoi(h.Get("X-RateLimit-Used"))
+	reset, _ := strconv.ParseInt(h.Get("X-RateLimit-Reset"), 10, 64)
+	return rateState{
+		Limit:     limit,
+		Remaining: remaining,
+		Used:      used,
+		Reset:     time.Unix(reset, 0),
+	}, true
+}
+
+func retryAfter(h http.Header) (time.Duration, bool) {
+	v := h.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		return time.Duration(secs) * time.Second, true
+	}
+	if at, err := http.ParseTime(v); err == nil {
+		return time.Until(at), true
+	}
+	return 0, false
+}
+
+// resourceFor guesses which rate limit resource a request counts against.
+func resourceFor(req *http.Request) string {
+	switch path := req.URL.Path; {
+	case strings.HasSuffix(path, "/graphql"):
+		return "graphql"
+	case strings.Contains(path, "/search/"):
+		return "search"
+	}
+	return "core"
+}
+
+// replayable reports whether req can be sent again.
+func replayable(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// backoff is the exponential delay before retry number attempt+1, half of
+// it randomized so concurrent clients don't retry in lockstep.
+func backoff(attempt int) time.Duration {
+	d := baseBackoff << attempt
+	if d > maxBackoff || d <= 0 {
+		d = maxBackoff
+	}
+	return d/2 + jitter(d/2)
+}
+
+func jitter(max time.Duration) time.Duration {
+	if max <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int64N(int64(max)))
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -1,5 +1,10 @@
 package github
 
+import (
+	"context"
+	"fmt"
+)
+
 type TreeEntry struct {
 	Path string `json:"path"`
 	Mode string `json:"mode"`
@@ -15,9 +20,18 @@ type TreeResponse struct {
 	Truncated bool        `json:"truncated"`
 }
 
-func (c *Client) GetFileTree(owner, repo, branch string) ([]TreeEntry, error) {
-	var t TreeResponse
+func (c *Client) GetFileTree(ctx context.Context, owner, repo, branch string) ([]TreeEntry, error) {
+	var entries []TreeEntry
+	truncated := false
 	// recursive=1 to get full tree
-	err := c.get("https://api.github.com/repos/"+owner+"/"+repo+"/git/trees/"+branch+"?recursive=1", &t)
-	return t.Tree, err
+	url := c.endpoint("repos/%s/%s/git/trees/%s?recursive=1", owner, repo, branch)
+	err := paginate(ctx, c, url, treePageCap, func(t TreeResponse) bool {
+		entries = append(entries, t.Tree...)
+		truncated = truncated || t.Truncated
+		return true
+	})
+	if err == nil && truncated {
+		err = fmt.Errorf("%w: GitHub limits recursive trees to 100,000 entries", ErrTruncated)
+	}
+	return entries, err
 }
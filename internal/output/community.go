@@ -0,0 +1,28 @@
+package output
+
+import (
+	"fmt"
+
+	"github.com/agnivo988/Repo-lyzer/internal/analyzer"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func PrintCommunityStandards(s analyzer.CommunityStandards) {
+	color := "#FF5F5F"
+	if s.Completeness >= 80 {
+		color = "#00FF87"
+	} else if s.Completeness >= 50 {
+		color = "#FFB000"
+	}
+	style := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(color))
+
+	fmt.Println(style.Render(fmt.Sprintf("📋 Community Standards : %d%% (%d of %d)", s.Completeness, s.Found, len(s.Items))))
+	for _, item := range s.Items {
+		if item.Found {
+			fmt.Printf("✅ %-16s %s\n", item.Item, item.Path)
+		} else {
+			fmt.Printf("❌ %-16s missing\n", item.Item)
+		}
+	}
+	fmt.Println()
+}
@@ -0,0 +1,45 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/agnivo988/Repo-lyzer/internal/analyzer"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func PrintDependencies(r analyzer.DependencyReport) {
+	style := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#00E5FF"))
+
+	fmt.Println(style.Render(fmt.Sprintf("🧩 Dependencies : %s", DependenciesText(r))))
+	if len(r.Manifests) == 0 && len(r.Errors) == 0 {
+		fmt.Println("No dependency manifests found")
+		fmt.Println()
+		return
+	}
+	var manifests []string
+	for _, m := range r.Manifests {
+		manifests = append(manifests, fmt.Sprintf("%s (%s)", m.Path, m.Ecosystem))
+	}
+	if len(manifests) > 0 {
+		fmt.Printf("Manifests      : %s\n", strings.Join(manifests, ", "))
+	}
+	if r.Direct > 0 {
+		fmt.Printf("Pinned         : %d of %d direct (%.0f%%)\n", r.Pinned, r.Direct, r.PinnedRatio()*100)
+	}
+	for _, o := range r.Overrides {
+		fmt.Printf("🔁 %s: %s %s => %s\n", o.Path, o.Directive, o.Name, o.Target)
+	}
+	for _, d := range r.DuplicateMajors {
+		fmt.Printf("⚠️ %s %s required at majors %s\n", d.Ecosystem, d.Name, strings.Join(d.Majors, ", "))
+	}
+	for _, e := range r.Errors {
+		fmt.Printf("❌ %s\n", e)
+	}
+	fmt.Println()
+}
+
+// DependenciesText sums up r, e.g. "12 direct, 40 transitive".
+func DependenciesText(r analyzer.DependencyReport) string {
+	return fmt.Sprintf("%d direct, %d transitive", r.Direct, r.Transitive)
+}
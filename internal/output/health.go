@@ -1,18 +1,19 @@
 package output
 
 import (
+	"context"
 	"fmt"
 	"os"
 
-	"github.com/charmbracelet/lipgloss"
+	"github.com/agnivo988/Repo-lyzer/internal/analyzer"
 	"github.com/agnivo988/Repo-lyzer/internal/github"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/olekukonko/tablewriter"
 )
 
-
-
 func PrintHealth(score int) {
-    color := "#FF5F5F"
-	label:= "🔴 Poor"
+	color := "#FF5F5F"
+	label := "🔴 Poor"
 
 	if score >= 80 {
 		color = "#00FF87"
@@ -20,38 +21,66 @@ func PrintHealth(score int) {
 	} else if score >= 60 {
 		color = "#FFB000"
 		label = "🟡 Good"
-	 }
+	}
 
-	 style := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(color))
+	style := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(color))
 
-     fmt.Println(style.Render(
-		fmt.Sprintf("\n🏆 Repo Health Score : %d/100 (%s)\n",score,label),
-	 ))
+	fmt.Println(style.Render(
+		fmt.Sprintf("\n🏆 Repo Health Score : %d/100 (%s)\n", score, label),
+	))
 }
-func PrintGitHubAPIStatus(client *github.Client) {
-	rateLimit, err := client.GetRateLimit()
-	if err != nil {
+
+// PrintHealthBreakdown lists how each signal of h contributed to the score.
+func PrintHealthBreakdown(h analyzer.HealthScore) {
+	fmt.Printf("Profile %s: base %s\n", h.Profile, formatPoints(h.Base))
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header([]string{"Signal", "Value", "Score", "Weight", "Points", "Why"})
+	for _, s := range h.Signals {
+		table.Append([]string{
+			s.Name,
+			formatPoints(s.Raw),
+			fmt.Sprintf("%.2f", s.Normalized),
+			formatPoints(s.Weight),
+			formatPoints(s.Points),
+			s.Explanation,
+		})
+	}
+	table.Render()
+	fmt.Println()
+}
+
+// formatPoints renders a value without decimals when it is whole.
+func formatPoints(v float64) string {
+	return fmt.Sprintf("%g", v)
+}
+
+func PrintGitHubAPIStatus(ctx context.Context, client *github.Client) {
+	quotas, err := client.Quotas(ctx)
+	if len(quotas) == 0 {
 		fmt.Println("⚠️ Unable to fetch GitHub API status")
 		return
 	}
 
 	mode := "Unauthenticated"
-if os.Getenv("GITHUB_TOKEN") != "" {
-	mode = "Authenticated"
-}
-
+	if client.Authenticated() {
+		mode = "Authenticated via " + client.AuthSource()
+	}
 
 	style := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#7AE7C7"))
 
 	fmt.Println(style.Render("🔐 GitHub API Status"))
 	fmt.Printf("Mode        : %s\n", mode)
-	fmt.Printf(
-		"Requests    : %d / %d\n",
-		rateLimit.Resources.Core.Remaining,
-		rateLimit.Resources.Core.Limit,
-	)
-	fmt.Printf(
-		"Resets At   : %s\n\n",
-		rateLimit.ResetTime().Format("15:04"),
-	)
+	if len(quotas) == 1 && err == nil {
+		fmt.Printf("Requests    : %d / %d\n", quotas[0].Remaining, quotas[0].Limit)
+		fmt.Printf("Resets At   : %s\n\n", quotas[0].Reset.Format("15:04"))
+		return
+	}
+
+	for _, q := range quotas {
+		fmt.Printf("%-12s: %d / %d (resets %s)\n", q.Source, q.Remaining, q.Limit, q.Reset.Format("15:04"))
+	}
+	if err != nil {
+		fmt.Println("⚠️ Unable to fetch the quota of every token")
+	}
+	fmt.Println()
 }
@@ -0,0 +1,64 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/agnivo988/Repo-lyzer/internal/analyzer"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// topLabels is how many labels the issue label histogram shows.
+const topLabels = 5
+
+func PrintIssueHealth(h analyzer.IssueHealth) {
+	color := "#FF5F5F"
+	switch h.Level {
+	case "Healthy", "No issues":
+		color = "#00FF87"
+	case "Fair":
+		color = "#FFB000"
+	}
+	style := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(color))
+
+	fmt.Println(style.Render(fmt.Sprintf("🐛 Issue Health : %s", h.Level)))
+	if h.Total == 0 {
+		fmt.Println()
+		return
+	}
+	fmt.Printf("Open / Closed  : %d / %d (%d stale)\n", h.Open, h.Closed, h.Stale)
+	fmt.Printf("First Response : %s\n", medianText(h.MedianTimeToFirstResponse, h.Responded))
+	fmt.Printf("Time to Close  : %s\n", medianText(h.MedianTimeToClose, h.Closed))
+	fmt.Printf("Backlog Age    : %s\n", BacklogAge(h))
+	fmt.Printf("Labels         : %s\n\n", IssueLabels(h, topLabels))
+}
+
+// BacklogAge renders the age distribution of the open issues of h, e.g.
+// "<1w 2 · <3m 1 · >1y 4".
+func BacklogAge(h analyzer.IssueHealth) string {
+	var parts []string
+	for _, b := range analyzer.IssueAgeBuckets {
+		if n := h.BacklogAge[b.Label]; n > 0 {
+			parts = append(parts, fmt.Sprintf("%s %d", b.Label, n))
+		}
+	}
+	if len(parts) == 0 {
+		return "n/a"
+	}
+	return strings.Join(parts, " · ")
+}
+
+// IssueLabels renders the n most used labels of h, e.g. "bug 8 · docs 3".
+func IssueLabels(h analyzer.IssueHealth, n int) string {
+	var parts []string
+	for i, l := range h.Labels {
+		if i == n {
+			break
+		}
+		parts = append(parts, fmt.Sprintf("%s %d", l.Name, l.Count))
+	}
+	if len(parts) == 0 {
+		return "n/a"
+	}
+	return strings.Join(parts, " · ")
+}
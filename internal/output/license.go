@@ -0,0 +1,58 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/agnivo988/Repo-lyzer/internal/analyzer"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func PrintLicense(r analyzer.LicenseReport) {
+	color := "#FF5F5F"
+	switch r.Category {
+	case analyzer.LicensePermissive:
+		color = "#00FF87"
+	case analyzer.LicenseWeakCopyleft, analyzer.LicenseStrongCopyleft:
+		color = "#FFB000"
+	}
+	style := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(color))
+
+	fmt.Println(style.Render(fmt.Sprintf("⚖️ License : %s", LicenseText(r))))
+	if r.FromAPI != "" {
+		fmt.Printf("GitHub         : %s\n", r.FromAPI)
+	}
+	if r.File != "" {
+		detected := "no known license"
+		if r.Detected != "" {
+			detected = fmt.Sprintf("%s (%.0f%% match)", r.Detected, r.Confidence*100)
+		}
+		fmt.Printf("%-14s : %s\n", r.File, detected)
+	}
+	if r.HeadersChecked > 0 {
+		fmt.Printf("Headers        : %d checked, %d disagree\n", r.HeadersChecked, len(r.Mismatches))
+		for _, m := range r.Mismatches {
+			fmt.Printf("⚠️ %s says %s\n", m.Path, m.License)
+		}
+	}
+	fmt.Println()
+}
+
+// LicenseText names the license of r with its category, e.g. "MIT
+// (permissive)", or "None found".
+func LicenseText(r analyzer.LicenseReport) string {
+	if r.SPDXID == "" {
+		return "None found"
+	}
+	return fmt.Sprintf("%s (%s)", r.SPDXID, r.Category)
+}
+
+// PrintLicensePolicy prints whether r passes the allowed-license policy.
+func PrintLicensePolicy(r analyzer.LicenseReport, policy []string) {
+	allowed := strings.Join(policy, ", ")
+	if r.Allowed(policy) {
+		fmt.Printf("✅ License Policy : %s is allowed (%s)\n\n", LicenseText(r), allowed)
+		return
+	}
+	fmt.Printf("❌ License Policy : %s is not allowed (%s)\n\n", LicenseText(r), allowed)
+}
@@ -0,0 +1,54 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/agnivo988/Repo-lyzer/internal/analyzer"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func PrintPRHealth(h analyzer.PRHealth) {
+	color := "#FF5F5F"
+	switch h.Level {
+	case "Healthy":
+		color = "#00FF87"
+	case "Fair":
+		color = "#FFB000"
+	}
+	style := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(color))
+
+	fmt.Println(style.Render(fmt.Sprintf("🔀 Pull Request Health : %s", h.Level)))
+	if h.Total == 0 {
+		fmt.Println()
+		return
+	}
+	fmt.Printf("Merge Rate     : %.0f%% (%d merged, %d open)\n", h.MergeRate*100, h.Merged, h.Open)
+	fmt.Printf("Abandoned      : %.0f%% (%d of %d)\n", h.AbandonedRatio*100, h.Abandoned, h.Total)
+	fmt.Printf("Time to Review : %s\n", medianText(h.MedianTimeToFirstReview, h.Reviewed))
+	fmt.Printf("Time to Merge  : %s\n", medianText(h.MedianTimeToMerge, h.Merged))
+	fmt.Printf("Sizes          : %s\n\n", PRSizes(h))
+}
+
+// PRSizes renders the size distribution of h, e.g. "XS 2 · S 4 · M 1".
+func PRSizes(h analyzer.PRHealth) string {
+	var parts []string
+	for _, b := range analyzer.PRSizeBuckets {
+		if n := h.Sizes[b.Label]; n > 0 {
+			parts = append(parts, fmt.Sprintf("%s %d", b.Label, n))
+		}
+	}
+	if len(parts) == 0 {
+		return "n/a"
+	}
+	return strings.Join(parts, " · ")
+}
+
+// medianText renders a median over n pull requests or issues, or "n/a" for none.
+func medianText(d time.Duration, n int) string {
+	if n == 0 {
+		return "n/a"
+	}
+	return fmt.Sprintf("%s (median of %d)", analyzer.HumanDuration(d), n)
+}
@@ -0,0 +1,41 @@
+package output
+
+import (
+	"fmt"
+
+	"github.com/agnivo988/Repo-lyzer/internal/analysis"
+)
+
+// StateIcon marks a section state in terminal output.
+func StateIcon(state analysis.State) string {
+	switch state {
+	case analysis.StateOK:
+		return "✅"
+	case analysis.StateTruncated:
+		return "✂️"
+	case analysis.StatePartial:
+		return "🟠"
+	}
+	return "❌"
+}
+
+// PrintUnavailable stands in for a section that could not be computed.
+func PrintUnavailable(title string, status analysis.SectionStatus) {
+	fmt.Println(WarningStyle.Render(fmt.Sprintf("\n%s : unavailable (%s)\n", title, status.Reason)))
+}
+
+// PrintDataQuality lists the sections of r that are incomplete or missing,
+// so nobody mistakes a failed fetch for an inactive repository. It prints
+// nothing when every section is complete.
+func PrintDataQuality(r *analysis.Result) {
+	degraded := r.Degraded()
+	if len(degraded) == 0 {
+		return
+	}
+
+	fmt.Println(WarningStyle.Bold(true).Render("\n⚠️ Data Quality"))
+	for _, section := range degraded {
+		status := r.Status(section)
+		fmt.Printf("%s %-13s %-10s %s\n", StateIcon(status.State), section, status.State, status.Reason)
+	}
+}
@@ -3,8 +3,8 @@ package output
 import (
 	"fmt"
 
-	"github.com/charmbracelet/lipgloss"
 	"github.com/agnivo988/Repo-lyzer/internal/analyzer"
+	"github.com/charmbracelet/lipgloss"
 )
 
 func PrintRecruiterSummary(s analyzer.RecruiterSummary) {
@@ -17,8 +17,25 @@ func PrintRecruiterSummary(s analyzer.RecruiterSummary) {
 	fmt.Println("⭐ Stars:", s.Stars)
 	fmt.Println("🍴 Forks:", s.Forks)
 	fmt.Println("📦 Commits (1y):", s.CommitsLastYear)
-	fmt.Println("👥 Contributors:", s.Contributors)
+	switch s.Bots {
+	case 0:
+		fmt.Println("👥 Contributors:", s.Contributors)
+	case 1:
+		fmt.Printf("👥 Contributors: %d (1 bot left out)\n", s.Contributors)
+	default:
+		fmt.Printf("👥 Contributors: %d (%d bots left out)\n", s.Contributors, s.Bots)
+	}
 	fmt.Println("🏗️ Maturity:", s.MaturityLevel, "(", s.MaturityScore, ")")
-	fmt.Println("⚠️ Bus Factor:", s.BusFactor, "-", s.BusRisk)
+	switch {
+	case s.BusFactor > 0 && s.BusMethod == analyzer.BusFromCommits:
+		fmt.Println("⚠️ Bus Factor:", s.BusFactor, "-", s.BusRisk, "(estimated from commit shares)")
+	case s.BusFactor > 0:
+		fmt.Println("⚠️ Bus Factor:", s.BusFactor, "-", s.BusRisk)
+	default:
+		fmt.Println("⚠️ Bus Factor:", s.BusRisk)
+	}
+	fmt.Println("📋 Community Standards:", s.CommunityStandards)
+	fmt.Println("🐛 Issue Health:", s.IssueHealth)
+	fmt.Println("🔀 PR Health:", s.PRHealth)
 	fmt.Println("🔥 Activity:", s.ActivityLevel)
 }
@@ -0,0 +1,49 @@
+package output
+
+import (
+	"fmt"
+
+	"github.com/agnivo988/Repo-lyzer/internal/analyzer"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func PrintReleaseHealth(h analyzer.ReleaseHealth) {
+	color := "#FF5F5F"
+	switch h.Level {
+	case "Active":
+		color = "#00FF87"
+	case "Slowing", "Tags only":
+		color = "#FFB000"
+	}
+	style := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(color))
+
+	fmt.Println(style.Render(fmt.Sprintf("🏷️ Release Health : %s", h.Level)))
+	if !h.HasReleases() {
+		fmt.Println()
+		return
+	}
+	fmt.Printf("Releases       : %d (%d pre, %.0f%%)\n", h.Releases, h.Prereleases, h.PrereleaseRatio*100)
+	fmt.Printf("Tags           : %d\n", h.Tags)
+	if h.Releases > 0 {
+		fmt.Printf("Last Release   : %s ago\n", analyzer.HumanDuration(h.SinceLastRelease))
+	}
+	if h.Cadence > 0 {
+		fmt.Printf("Cadence        : every %s (median)\n", analyzer.HumanDuration(h.Cadence))
+	}
+	fmt.Printf("Semver         : %.0f%%\n", h.SemverRatio*100)
+	fmt.Printf("Changelog      : %s\n\n", ChangelogText(h))
+}
+
+// ChangelogText says how h documents changes, e.g. "release notes on 4 of
+// 6".
+func ChangelogText(h analyzer.ReleaseHealth) string {
+	switch {
+	case h.Changelog && h.WithNotes > 0:
+		return fmt.Sprintf("changelog file, release notes on %d of %d", h.WithNotes, h.Releases)
+	case h.Changelog:
+		return "changelog file"
+	case h.WithNotes > 0:
+		return fmt.Sprintf("release notes on %d of %d", h.WithNotes, h.Releases)
+	}
+	return "none"
+}
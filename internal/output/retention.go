@@ -0,0 +1,82 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/agnivo988/Repo-lyzer/internal/analyzer"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// newcomerMonths is how many of the latest months the newcomer counts show.
+const newcomerMonths = 6
+
+func PrintRetention(r analyzer.RetentionReport) {
+	color := "#00E5FF"
+	if r.Eligible > 0 {
+		switch {
+		case r.Retention >= 0.5:
+			color = "#00FF87"
+		case r.Retention >= 0.25:
+			color = "#FFB000"
+		default:
+			color = "#FF5F5F"
+		}
+	}
+	style := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(color))
+
+	fmt.Println(style.Render("🌱 Contributor Retention : " + RetentionText(r)))
+	if r.Contributors == 0 {
+		fmt.Println()
+		return
+	}
+	fmt.Printf("Contributors   : %d with %d commits since %s\n", r.Contributors, r.Commits, r.Since.Format("2006-01-02"))
+	if r.Truncated {
+		fmt.Printf("Newcomers      : %d since %s (history cut off before %s)\n", r.Newcomers, r.NewcomersSince.Format("2006-01-02"), r.Since.Format("2006-01-02"))
+	}
+	fmt.Printf("One-time       : %d of %d (%.0f%%)\n", r.OneTime, r.Newcomers, r.OneTimeRatio*100)
+	fmt.Printf("New per month  : %s\n", NewcomersText(r, newcomerMonths))
+	fmt.Printf("Core team      : %d people behind 80%% of commits\n", len(r.Core))
+	for _, m := range r.Core {
+		line := fmt.Sprintf("%-12s %4d commits, last %d days ago", m.Author, m.Commits, m.DaysSince)
+		if m.Quiet {
+			fmt.Println("⚠️ " + line + ", gone quiet")
+		} else {
+			fmt.Println("👤 " + line)
+		}
+	}
+	fmt.Println()
+}
+
+// RetentionText renders the 90-day retention of r, e.g. "50% (1 of 2 stayed
+// 90 days)", naming when the newcomers start if the history was cut short.
+func RetentionText(r analyzer.RetentionReport) string {
+	if r.Truncated {
+		since := r.NewcomersSince.Format("2006-01-02")
+		if r.Eligible == 0 {
+			return "n/a (no first commits since " + since + " are 90 days old)"
+		}
+		return fmt.Sprintf("%.0f%% (%d of %d newcomers since %s stayed 90 days)", r.Retention*100, r.Retained, r.Eligible, since)
+	}
+	if r.Eligible == 0 {
+		return "n/a (no first commits 90 days old)"
+	}
+	return fmt.Sprintf("%.0f%% (%d of %d stayed 90 days)", r.Retention*100, r.Retained, r.Eligible)
+}
+
+// NewcomersText renders the newcomers of the last n months of r, e.g.
+// "2024-05 2 · 2024-06 0".
+func NewcomersText(r analyzer.RetentionReport, n int) string {
+	months := r.NewPerMonth
+	if len(months) > n {
+		months = months[len(months)-n:]
+	}
+	var parts []string
+	for _, m := range months {
+		parts = append(parts, fmt.Sprintf("%s %d", m.Month, m.New))
+	}
+	if len(parts) == 0 {
+		return "n/a"
+	}
+	return strings.Join(parts, " · ")
+}
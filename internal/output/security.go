@@ -0,0 +1,48 @@
+package output
+
+import (
+	"fmt"
+
+	"github.com/agnivo988/Repo-lyzer/internal/analyzer"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var severityColors = map[analyzer.Severity]string{
+	analyzer.SeverityCritical: "#FF5F5F",
+	analyzer.SeverityHigh:     "#FF5F5F",
+	analyzer.SeverityMedium:   "#FFB000",
+	analyzer.SeverityLow:      "#00E5FF",
+	analyzer.SeverityUnknown:  "#00E5FF",
+}
+
+func PrintSecurity(r analyzer.SecurityReport) {
+	color := "#FF5F5F"
+	switch r.Level {
+	case "Strong":
+		color = "#00FF87"
+	case "Moderate":
+		color = "#FFB000"
+	}
+	style := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(color))
+
+	fmt.Println(style.Render(fmt.Sprintf("🔒 Security : %d/100 (%s)", r.Score, r.Level)))
+	for _, c := range r.Checks {
+		mark := "❌"
+		switch {
+		case c.Skipped:
+			mark = "➖"
+		case c.Passed:
+			mark = "✅"
+		}
+		fmt.Printf("%s %-18s %s\n", mark, c.Name, c.Detail)
+	}
+	for _, f := range r.Findings {
+		severity := lipgloss.NewStyle().Foreground(lipgloss.Color(severityColors[f.Severity])).Render(fmt.Sprintf("%-8s", f.Severity))
+		if f.Path != "" {
+			fmt.Printf("%s %s: %s\n", severity, f.Path, f.Message)
+		} else {
+			fmt.Printf("%s %s\n", severity, f.Message)
+		}
+	}
+	fmt.Println()
+}
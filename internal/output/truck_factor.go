@@ -0,0 +1,42 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/agnivo988/Repo-lyzer/internal/analyzer"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// keyFilesShown caps the files listed for each key person.
+const keyFilesShown = 3
+
+func PrintTruckFactor(r analyzer.TruckFactorReport) {
+	if r.Files == 0 {
+		fmt.Println("🚚 Truck Factor : no file authors in the fetched commits")
+		fmt.Println()
+		return
+	}
+	color := "#00FF87"
+	switch r.Factor {
+	case 1:
+		color = "#FF5F5F"
+	case 2:
+		color = "#FFB000"
+	}
+	style := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(color))
+
+	fmt.Println(style.Render(fmt.Sprintf("🚚 Truck Factor : %d (%s)", r.Factor, r.Risk)))
+	fmt.Printf("Based on       : %d files changed by %d commits\n", r.Files, r.Commits)
+	fmt.Printf("Without them   : %d of %d files have no author left\n", r.Orphaned, r.Files)
+	for _, p := range r.KeyPeople {
+		files := p.Files
+		more := ""
+		if len(files) > keyFilesShown {
+			more = fmt.Sprintf(" and %d more", len(files)-keyFilesShown)
+			files = files[:keyFilesShown]
+		}
+		fmt.Printf("👤 %-12s %3d files: %s%s\n", p.Author, len(p.Files), strings.Join(files, ", "), more)
+	}
+	fmt.Println()
+}
@@ -0,0 +1,37 @@
+package output
+
+import (
+	"fmt"
+
+	"github.com/agnivo988/Repo-lyzer/internal/analyzer"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func PrintVulnerabilities(r analyzer.VulnerabilityReport) {
+	color := "#00FF87"
+	if len(r.Vulnerabilities) > 0 {
+		color = "#FF5F5F"
+	}
+	style := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(color))
+
+	fmt.Println(style.Render(fmt.Sprintf("🚨 Vulnerabilities : %d in %d packages", len(r.Vulnerabilities), r.Packages())))
+	fmt.Printf("Database       : %s (%d advisories)\n", r.Source, r.Advisories)
+	fmt.Printf("Checked        : %d dependencies with exact versions\n", r.Checked)
+	if r.Unchecked > 0 {
+		fmt.Printf("Not checked    : %d dependencies without an exact version\n", r.Unchecked)
+	}
+	for _, v := range r.Vulnerabilities {
+		severity := lipgloss.NewStyle().Foreground(lipgloss.Color(severityColors[v.Severity])).Render(fmt.Sprintf("%-8s", v.Severity))
+		fmt.Printf("%s %s %s@%s %s", severity, v.Ecosystem, v.Package, v.Version, v.ID)
+		if v.Fixed != "" {
+			fmt.Printf(" (fixed in %s)", v.Fixed)
+		} else {
+			fmt.Print(" (no fix)")
+		}
+		if v.Summary != "" {
+			fmt.Printf(": %s", v.Summary)
+		}
+		fmt.Println()
+	}
+	fmt.Println()
+}
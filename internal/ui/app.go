@@ -1,10 +1,13 @@
 package ui
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
-	"github.com/agnivo988/Repo-lyzer/internal/analyzer"
+	"github.com/agnivo988/Repo-lyzer/internal/analysis"
 	"github.com/agnivo988/Repo-lyzer/internal/github"
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
@@ -13,6 +16,9 @@ import (
 
 type sessionState int
 
+// analysisTimeout bounds a whole analysis started from the TUI.
+const analysisTimeout = 2 * time.Minute
+
 const (
 	stateMenu sessionState = iota
 	stateInput
@@ -30,10 +36,17 @@ type MainModel struct {
 	err          error
 	windowWidth  int
 	windowHeight int
-	analysisType string // quick, detailed, custom
+	analysisType string             // quick, detailed, custom
+	cancel       context.CancelFunc // cancels the in-flight analysis, if any
+
+	// source and opts are what repositories are analyzed with.
+	source github.RepoSource
+	opts   analysis.Options
 }
 
-func NewMainModel() MainModel {
+// NewMainModel returns the TUI's model, analyzing repositories fetched from
+// source with opts.
+func NewMainModel(source github.RepoSource, opts analysis.Options) MainModel {
 	s := spinner.New()
 	s.Spinner = spinner.Dot
 	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
@@ -43,6 +56,8 @@ func NewMainModel() MainModel {
 		menu:      NewMenuModel(),
 		spinner:   s,
 		dashboard: NewDashboardModel(),
+		source:    source,
+		opts:      opts,
 	}
 }
 
@@ -87,8 +102,11 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			switch msg.Type {
 			case tea.KeyEnter:
 				if m.input != "" {
+					ctx, cancel := context.WithTimeout(context.Background(), analysisTimeout)
+					m.cancel = cancel
+					m.err = nil
 					m.state = stateLoading
-					cmds = append(cmds, m.analyzeRepo(m.input))
+					cmds = append(cmds, m.analyzeRepo(ctx, m.input))
 				}
 			case tea.KeyBackspace:
 				if len(m.input) > 0 {
@@ -105,12 +123,19 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.spinner, cmd = m.spinner.Update(msg)
 		cmds = append(cmds, cmd)
 
+		if key, ok := msg.(tea.KeyMsg); ok && key.Type == tea.KeyEsc {
+			m.cancelAnalysis()
+			m.state = stateInput
+			m.progress = nil
+		}
 		if result, ok := msg.(AnalysisResult); ok {
+			m.cancelAnalysis()
 			m.dashboard.SetData(result)
 			m.state = stateDashboard
 			m.progress = nil
 		}
 		if err, ok := msg.(error); ok {
+			m.cancelAnalysis()
 			m.err = err
 			m.state = stateInput // Go back to input on error
 			m.progress = nil
@@ -184,7 +209,7 @@ func (m MainModel) inputView() string {
 			SubtleStyle.Render("Format: owner/repo  •  Press Enter to run")
 
 	if m.err != nil {
-		inputContent += "\n\n" + ErrorStyle.Render(fmt.Sprintf("Error: %v", m.err))
+		inputContent += "\n\n" + ErrorStyle.Render(errorMessage(m.err))
 	}
 
 	box := BoxStyle.Render(inputContent)
@@ -202,62 +227,42 @@ func (m MainModel) inputView() string {
 	)
 }
 
-func (m MainModel) analyzeRepo(repoName string) tea.Cmd {
+// cancelAnalysis aborts the in-flight analysis and releases its context.
+func (m *MainModel) cancelAnalysis() {
+	if m.cancel != nil {
+		m.cancel()
+		m.cancel = nil
+	}
+}
+
+func (m MainModel) analyzeRepo(ctx context.Context, repoName string) tea.Cmd {
 	return func() tea.Msg {
-		parts := strings.Split(repoName, "/")
-		if len(parts) != 2 {
-			return fmt.Errorf("repository must be in owner/repo format")
+		msg := m.runAnalysis(ctx, repoName)
+		// A cancelled analysis was abandoned with ESC; drop whatever it
+		// produced so it can't land on a later loading screen.
+		if errors.Is(ctx.Err(), context.Canceled) {
+			return nil
 		}
+		return msg
+	}
+}
 
-		tracker := NewProgressTracker()
+func (m MainModel) runAnalysis(ctx context.Context, repoName string) tea.Msg {
+	parts := strings.Split(repoName, "/")
+	if len(parts) != 2 {
+		return fmt.Errorf("repository must be in owner/repo format")
+	}
 
-		// Stage 1: Fetch repository
-		client := github.NewClient()
-		repo, err := client.GetRepo(parts[0], parts[1])
-		if err != nil {
-			return err
-		}
-		tracker.NextStage()
-
-		// Stage 2: Analyze commits
-		commits, _ := client.GetCommits(parts[0], parts[1], 365)
-		tracker.NextStage()
-
-		// Stage 3: Analyze contributors
-		contributors, _ := client.GetContributors(parts[0], parts[1])
-		tracker.NextStage()
-
-		// Stage 4: Analyze languages
-		languages, _ := client.GetLanguages(parts[0], parts[1])
-		fileTree, _ := client.GetFileTree(parts[0], parts[1], repo.DefaultBranch)
-		tracker.NextStage()
-
-		// Stage 5: Compute metrics
-		score := analyzer.CalculateHealth(repo, commits)
-		busFactor, busRisk := analyzer.BusFactor(contributors)
-		maturityScore, maturityLevel := analyzer.RepoMaturityScore(repo, len(commits), len(contributors), false)
-		tracker.NextStage()
-
-		// Mark complete
-		tracker.NextStage()
-
-		return AnalysisResult{
-			Repo:          repo,
-			Commits:       commits,
-			Contributors:  contributors,
-			FileTree:      fileTree,
-			Languages:     languages,
-			HealthScore:   score,
-			BusFactor:     busFactor,
-			BusRisk:       busRisk,
-			MaturityScore: maturityScore,
-			MaturityLevel: maturityLevel,
-		}
+	result, err := analysis.Run(ctx, m.source, parts[0], parts[1], m.opts)
+	if err != nil {
+		return err
 	}
+	return *result
 }
 
-func Run() error {
-	p := tea.NewProgram(NewMainModel(), tea.WithAltScreen())
+// Run starts the TUI, analyzing repositories fetched from source with opts.
+func Run(source github.RepoSource, opts analysis.Options) error {
+	p := tea.NewProgram(NewMainModel(source, opts), tea.WithAltScreen())
 	_, err := p.Run()
 	return err
 }
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/agnivo988/Repo-lyzer/internal/analysis"
 	"github.com/agnivo988/Repo-lyzer/internal/analyzer"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -92,11 +93,29 @@ func (m DashboardModel) View() string {
 
 	// Metrics Column
 	metrics := fmt.Sprintf(
-		"Health Score: %d\nBus Factor: %d (%s)\nMaturity: %s (%d)",
-		m.data.HealthScore,
-		m.data.BusFactor, m.data.BusRisk,
-		m.data.MaturityLevel, m.data.MaturityScore,
+		"Health Score: %s\nBus Factor: %s\nMaturity: %s\nCommunity: %s\nLicense: %s\nSecurity: %s\nReleases: %s\nIssue Health: %s\nPR Health: %s",
+		scoreText(m.data, analysis.SectionHealth, fmt.Sprintf("%d", m.data.HealthScore)),
+		scoreText(m.data, analysis.SectionBusFactor, busText(m.data)),
+		scoreText(m.data, analysis.SectionMaturity, fmt.Sprintf("%s (%d)", m.data.MaturityLevel, m.data.MaturityScore)),
+		scoreText(m.data, analysis.SectionCommunityStandards, fmt.Sprintf("%d%% (missing: %s)", m.data.CommunityStandards.Completeness, missingCommunityItems(m.data.CommunityStandards))),
+		scoreText(m.data, analysis.SectionLicensing, licenseText(m.data.License)),
+		scoreText(m.data, analysis.SectionSecurity, securityText(m.data.Security)),
+		scoreText(m.data, analysis.SectionReleaseHealth, releaseText(m.data.ReleaseHealth)),
+		scoreText(m.data, analysis.SectionIssueHealth, issueHealthText(m.data.IssueHealth)),
+		scoreText(m.data, analysis.SectionPRHealth, prHealthText(m.data.PRHealth)),
 	)
+	if m.data.Available(analysis.SectionHealth) {
+		metrics += fmt.Sprintf("\n\nHealth Breakdown (%s, base %g)", m.data.Health.Profile, m.data.Health.Base)
+		for _, line := range healthBreakdown(m.data.Health) {
+			metrics += "\n" + line
+		}
+	}
+	if notes := qualityNotes(m.data); len(notes) > 0 {
+		metrics += "\n\n⚠️ Data Quality"
+		for _, note := range notes {
+			metrics += "\n" + note
+		}
+	}
 	metricsBox := BoxStyle.Render(metrics)
 
 	// Charts
@@ -122,9 +141,19 @@ func (m DashboardModel) View() string {
 	}
 	treeBox := BoxStyle.Render(treeContent)
 
+	// Dependencies
+	depsContent := "🧩 Dependencies: " + scoreText(m.data, analysis.SectionDependencies, dependencyText(m.data.Dependencies))
+	if m.data.Available(analysis.SectionDependencies) {
+		for _, line := range dependencyLines(m.data.Dependencies) {
+			depsContent += "\n" + line
+		}
+	}
+	depsBox := BoxStyle.Render(depsContent)
+
 	// Layout
 	row1 := lipgloss.JoinHorizontal(lipgloss.Top, metricsBox, chartBox)
-	content := lipgloss.JoinVertical(lipgloss.Left, header, row1, treeBox)
+	row2 := lipgloss.JoinHorizontal(lipgloss.Top, treeBox, depsBox)
+	content := lipgloss.JoinVertical(lipgloss.Left, header, row1, row2)
 
 	if m.showExport {
 		exportMenu := BoxStyle.Render("Export Options:\n[J] JSON\n[M] Markdown")
@@ -0,0 +1,29 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/agnivo988/Repo-lyzer/internal/github"
+)
+
+// errorMessage renders an analysis error for the input screen, giving the
+// typed GitHub client errors a message of their own.
+func errorMessage(err error) string {
+	switch {
+	case errors.Is(err, github.ErrNotFound):
+		return "🔍 Repository not found. Check the owner/repo spelling (private repositories need GITHUB_TOKEN)."
+	case errors.Is(err, github.ErrUnauthorized):
+		return "🔑 GitHub rejected the credentials. Check GITHUB_TOKEN."
+	case errors.Is(err, github.ErrRateLimited):
+		if reset, ok := github.RateLimitReset(err); ok {
+			return fmt.Sprintf("⏳ GitHub API rate limit exceeded until %s. Set GITHUB_TOKEN for a higher limit.",
+				reset.Format("15:04"))
+		}
+		return "⏳ GitHub API rate limit exceeded. Set GITHUB_TOKEN for a higher limit."
+	case errors.Is(err, context.DeadlineExceeded):
+		return "⌛ The analysis timed out. Try again in a moment."
+	}
+	return fmt.Sprintf("Error: %v", err)
+}
@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+
+	"github.com/agnivo988/Repo-lyzer/internal/analysis"
 )
 
 func ExportJSON(data AnalysisResult, filename string) error {
@@ -26,10 +28,74 @@ func ExportMarkdown(data AnalysisResult, filename string) error {
 	defer file.Close()
 
 	md := fmt.Sprintf("# Analysis for %s\n\n", data.Repo.FullName)
-	md += fmt.Sprintf("## Health Score: %d\n", data.HealthScore)
-	md += fmt.Sprintf("## Bus Factor: %d (%s)\n", data.BusFactor, data.BusRisk)
-	md += fmt.Sprintf("## Maturity: %s (%d)\n", data.MaturityLevel, data.MaturityScore)
-	
+	md += fmt.Sprintf("## Health Score: %s\n", scoreText(data, analysis.SectionHealth, fmt.Sprintf("%d", data.HealthScore)))
+	if data.Available(analysis.SectionHealth) {
+		md += fmt.Sprintf("\nProfile %s, base %g\n\n", data.Health.Profile, data.Health.Base)
+		md += "| Signal | Value | Score | Weight | Points | Why |\n|---|---|---|---|---|---|\n"
+		for _, s := range data.Health.Signals {
+			md += fmt.Sprintf("| %s | %g | %.2f | %g | %g | %s |\n", s.Name, s.Raw, s.Normalized, s.Weight, s.Points, s.Explanation)
+		}
+		md += "\n"
+	}
+	md += fmt.Sprintf("## Bus Factor: %s\n", scoreText(data, analysis.SectionBusFactor, busText(data)))
+	if data.Available(analysis.SectionTruckFactor) && data.TruckFactor.Factor > 0 {
+		md += fmt.Sprintf("\nFrom the authors of %d files in %d commits; without these people %d files have no author left:\n\n", data.TruckFactor.Files, data.TruckFactor.Commits, data.TruckFactor.Orphaned)
+		for _, p := range data.TruckFactor.KeyPeople {
+			md += fmt.Sprintf("- %s (%d files)\n", p.Author, len(p.Files))
+		}
+		md += "\n"
+	}
+	if data.Available(analysis.SectionRetention) {
+		md += fmt.Sprintf("## Contributor Retention: %s\n", retentionText(data.Retention))
+		for _, line := range retentionLines(data.Retention) {
+			md += "- " + line + "\n"
+		}
+	}
+	md += fmt.Sprintf("## Maturity: %s\n", scoreText(data, analysis.SectionMaturity, fmt.Sprintf("%s (%d)", data.MaturityLevel, data.MaturityScore)))
+
+	md += fmt.Sprintf("## Community Standards: %s\n", scoreText(data, analysis.SectionCommunityStandards, fmt.Sprintf("%d%%", data.CommunityStandards.Completeness)))
+	if data.Available(analysis.SectionCommunityStandards) {
+		for _, item := range data.CommunityStandards.Items {
+			check := " "
+			if item.Found {
+				check = "x"
+			}
+			md += fmt.Sprintf("- [%s] %s\n", check, item.Item)
+		}
+	}
+	md += fmt.Sprintf("## License: %s\n", scoreText(data, analysis.SectionLicensing, licenseText(data.License)))
+	if data.Available(analysis.SectionLicensing) {
+		for _, m := range data.License.Mismatches {
+			md += fmt.Sprintf("- %s says %s\n", m.Path, m.License)
+		}
+	}
+	md += fmt.Sprintf("## Security: %s\n", scoreText(data, analysis.SectionSecurity, securityText(data.Security)))
+	if data.Available(analysis.SectionSecurity) {
+		for _, f := range data.Security.Findings {
+			where := ""
+			if f.Path != "" {
+				where = " `" + f.Path + "`"
+			}
+			md += fmt.Sprintf("- **%s**%s %s\n", f.Severity, where, f.Message)
+		}
+	}
+	md += fmt.Sprintf("## Dependencies: %s\n", scoreText(data, analysis.SectionDependencies, dependencyText(data.Dependencies)))
+	if data.Available(analysis.SectionDependencies) {
+		for _, line := range dependencyLines(data.Dependencies) {
+			md += "- " + line + "\n"
+		}
+	}
+	md += fmt.Sprintf("## Releases: %s\n", scoreText(data, analysis.SectionReleaseHealth, releaseText(data.ReleaseHealth)))
+	md += fmt.Sprintf("## Issue Health: %s\n", scoreText(data, analysis.SectionIssueHealth, issueHealthText(data.IssueHealth)))
+	md += fmt.Sprintf("## PR Health: %s\n", scoreText(data, analysis.SectionPRHealth, prHealthText(data.PRHealth)))
+
+	if notes := qualityNotes(data); len(notes) > 0 {
+		md += "\n## Data Quality\n"
+		for _, note := range notes {
+			md += "- " + note + "\n"
+		}
+	}
+
 	md += "\n## File Tree (Top 20)\n"
 	limit := 20
 	if len(data.FileTree) < limit {
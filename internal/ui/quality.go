@@ -0,0 +1,202 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/agnivo988/Repo-lyzer/internal/analysis"
+	"github.com/agnivo988/Repo-lyzer/internal/analyzer"
+)
+
+// scoreText renders value for a score backed by section, or "n/a" when the
+// score could not be computed.
+func scoreText(data AnalysisResult, section analysis.Section, value string) string {
+	if !data.Available(section) {
+		return "n/a"
+	}
+	return value
+}
+
+// qualityNotes describes each section of data that is incomplete or
+// missing, one line per section.
+func qualityNotes(data AnalysisResult) []string {
+	var notes []string
+	for _, section := range data.Degraded() {
+		status := data.Status(section)
+		notes = append(notes, fmt.Sprintf("%s: %s (%s)", section, status.State, status.Reason))
+	}
+	return notes
+}
+
+// busText summarizes the bus factor of data on one line, e.g. "2 (Medium
+// Risk)", saying when it was only estimated from commit shares.
+func busText(data AnalysisResult) string {
+	if data.BusMethod == analyzer.BusFromCommits {
+		return fmt.Sprintf("%d (%s, estimated from commit shares)", data.BusFactor, data.BusRisk)
+	}
+	return fmt.Sprintf("%d (%s)", data.BusFactor, data.BusRisk)
+}
+
+// prHealthText summarizes h on one line, e.g. "Healthy, 78% merged in 2.0d".
+func prHealthText(h analyzer.PRHealth) string {
+	if h.Total == 0 || h.Merged == 0 {
+		return h.Level
+	}
+	return fmt.Sprintf("%s, %.0f%% merged in %s", h.Level, h.MergeRate*100, analyzer.HumanDuration(h.MedianTimeToMerge))
+}
+
+// issueHealthText summarizes h on one line, e.g. "Healthy, 12 open, first
+// response in 8h".
+func issueHealthText(h analyzer.IssueHealth) string {
+	if h.Total == 0 {
+		return h.Level
+	}
+	text := fmt.Sprintf("%s, %d open", h.Level, h.Open)
+	if h.Responded > 0 {
+		text += ", first response in " + analyzer.HumanDuration(h.MedianTimeToFirstResponse)
+	}
+	return text
+}
+
+// releaseText summarizes h on one line, e.g. "Active, 6 releases every
+// 90.0d".
+func releaseText(h analyzer.ReleaseHealth) string {
+	if h.Releases == 0 {
+		return h.Level
+	}
+	text := fmt.Sprintf("%s, %d releases", h.Level, h.Releases)
+	if h.Cadence > 0 {
+		text += " every " + analyzer.HumanDuration(h.Cadence)
+	}
+	return text
+}
+
+// licenseText summarizes r on one line, e.g. "MIT (permissive), 1 header
+// disagrees".
+func licenseText(r analyzer.LicenseReport) string {
+	if r.SPDXID == "" {
+		return "None found"
+	}
+	text := fmt.Sprintf("%s (%s)", r.SPDXID, r.Category)
+	switch n := len(r.Mismatches); {
+	case n == 1:
+		text += ", 1 header disagrees"
+	case n > 1:
+		text += fmt.Sprintf(", %d headers disagree", n)
+	}
+	return text
+}
+
+// securityText summarizes r on one line, e.g. "50/100 (Moderate), 1
+// critical finding".
+func securityText(r analyzer.SecurityReport) string {
+	text := fmt.Sprintf("%d/100 (%s)", r.Score, r.Level)
+	critical := 0
+	for _, f := range r.Findings {
+		if f.Severity == analyzer.SeverityCritical {
+			critical++
+		}
+	}
+	switch {
+	case critical == 1:
+		text += ", 1 critical finding"
+	case critical > 1:
+		text += fmt.Sprintf(", %d critical findings", critical)
+	}
+	return text
+}
+
+// dependencyText summarizes r on one line, e.g. "3 direct (100% pinned),
+// 1 transitive".
+func dependencyText(r analyzer.DependencyReport) string {
+	if len(r.Manifests) == 0 {
+		return "No manifests"
+	}
+	text := fmt.Sprintf("%d direct", r.Direct)
+	if r.Direct > 0 {
+		text += fmt.Sprintf(" (%.0f%% pinned)", r.PinnedRatio()*100)
+	}
+	return text + fmt.Sprintf(", %d transitive", r.Transitive)
+}
+
+// dependencyLines describes the manifests, overrides and duplicate majors
+// of r, one per line.
+func dependencyLines(r analyzer.DependencyReport) []string {
+	var lines []string
+	for _, m := range r.Manifests {
+		lines = append(lines, fmt.Sprintf("%s (%s): %d", m.Path, m.Ecosystem, len(m.Dependencies)))
+	}
+	for _, o := range r.Overrides {
+		lines = append(lines, fmt.Sprintf("%s %s => %s", o.Directive, o.Name, o.Target))
+	}
+	for _, d := range r.DuplicateMajors {
+		lines = append(lines, fmt.Sprintf("%s at majors %s", d.Name, strings.Join(d.Majors, ", ")))
+	}
+	return lines
+}
+
+// retentionText summarizes r on one line, e.g. "50% stay 90 days, core
+// team of 3".
+func retentionText(r analyzer.RetentionReport) string {
+	if r.Contributors == 0 {
+		return "No commits"
+	}
+	text := "n/a"
+	if r.Eligible > 0 {
+		text = fmt.Sprintf("%.0f%%", r.Retention*100)
+	}
+	if r.Truncated {
+		text += " of newcomers since " + r.NewcomersSince.Format("2006-01-02")
+	}
+	return text + fmt.Sprintf(" stay 90 days, core team of %d", len(r.Core))
+}
+
+// retentionLines describes the newcomers and the core members of r, one
+// per line.
+func retentionLines(r analyzer.RetentionReport) []string {
+	lines := []string{fmt.Sprintf("%d of %d contributors made one commit", r.OneTime, r.Newcomers)}
+	if r.Truncated {
+		lines = []string{
+			fmt.Sprintf("history cut off before %s: newcomers counted from %s", r.Since.Format("2006-01-02"), r.NewcomersSince.Format("2006-01-02")),
+			fmt.Sprintf("%d of %d newcomers made one commit", r.OneTime, r.Newcomers),
+		}
+	}
+	for _, m := range r.NewPerMonth {
+		if m.New > 0 {
+			lines = append(lines, fmt.Sprintf("%s: %d new", m.Month, m.New))
+		}
+	}
+	for _, m := range r.Core {
+		line := fmt.Sprintf("core: %s, %d commits, last %d days ago", m.Author, m.Commits, m.DaysSince)
+		if m.Quiet {
+			line += ", gone quiet"
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// healthBreakdown describes each signal of h on one line, e.g.
+// "commits +20 (150 recent commits (more than 10))".
+func healthBreakdown(h analyzer.HealthScore) []string {
+	var lines []string
+	for _, s := range h.Signals {
+		lines = append(lines, fmt.Sprintf("%s %+g (%s)", s.Name, s.Points, s.Explanation))
+	}
+	return lines
+}
+
+// missingCommunityItems lists the community checklist items s lacks, e.g.
+// "SECURITY, FUNDING", or "none".
+func missingCommunityItems(s analyzer.CommunityStandards) string {
+	var missing []string
+	for _, item := range s.Items {
+		if !item.Found {
+			missing = append(missing, item.Item)
+		}
+	}
+	if len(missing) == 0 {
+		return "none"
+	}
+	return strings.Join(missing, ", ")
+}
@@ -1,16 +1,6 @@
 package ui
 
-import "github.com/agnivo988/Repo-lyzer/internal/github"
+import "github.com/agnivo988/Repo-lyzer/internal/analysis"
 
-type AnalysisResult struct {
-	Repo          *github.Repo
-	Commits       []github.Commit
-	Contributors  []github.Contributor
-	FileTree      []github.TreeEntry
-	Languages     map[string]int
-	HealthScore   int
-	BusFactor     int
-	BusRisk       string
-	MaturityScore int
-	MaturityLevel string
-}
+// AnalysisResult is what the dashboard and the exports render.
+type AnalysisResult = analysis.Result
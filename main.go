@@ -3,5 +3,5 @@ package main
 import "github.com/agnivo988/Repo-lyzer/cmd"
 
 func main() {
-	cmd.RunMenu()
+	cmd.Execute()
 }